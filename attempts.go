@@ -0,0 +1,64 @@
+package repeater
+
+import (
+	"context"
+	"iter"
+)
+
+// Attempt is yielded by Attempts, an attempt which neither called Done nor Abort is failed and retried
+type Attempt struct {
+	// zero is the initial attempt, retries start from 1
+	Number uint64
+
+	done  bool
+	state *repeatState
+}
+
+// Done finishes the repeat successfully, the iteration stops after the current attempt
+func (a *Attempt) Done() {
+	a.done = true
+}
+
+// Abort stops the repeat without success and without retries
+func (a *Attempt) Abort() {
+	a.state.aborted = true
+}
+
+func Attempts(ctx context.Context, progression DurationProgression, retryCount uint64) iter.Seq[*Attempt] {
+	rp := New(progression)
+
+	return rp.Attempts(ctx, retryCount)
+}
+
+// Attempts yields an Attempt for the initial call and every retry, sleeping between iterations
+// like RepeatContext, breaking the loop aborts the repeat:
+//
+//	for attempt := range rp.Attempts(ctx, 3) {
+//		err := send(ctx)
+//		switch {
+//		case err == nil:
+//			attempt.Done()
+//		case errors.Is(err, ErrBadRequest):
+//			attempt.Abort()
+//		}
+//	}
+func (r *Repeater) Attempts(ctx context.Context, retryCount uint64) iter.Seq[*Attempt] {
+	return func(yield func(*Attempt) bool) {
+		state := r.start()
+
+		attempt := &Attempt{state: &state}
+
+		r.repeatContext(ctx, &state, func(context.Context) bool {
+			attempt.Number = state.attempt
+			attempt.done = false
+
+			if !yield(attempt) {
+				state.aborted = true
+
+				return false
+			}
+
+			return attempt.done
+		}, retryCount)
+	}
+}
@@ -0,0 +1,111 @@
+package repeater_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Attempts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("done", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &recorderMock{}
+
+		rp := repeater.New(repeater.ConstantProgression(time.Millisecond), repeater.WithRecorder(recorder))
+
+		var numbers []uint64
+
+		for attempt := range rp.Attempts(context.Background(), 3) {
+			numbers = append(numbers, attempt.Number)
+
+			if attempt.Number == 1 {
+				attempt.Done()
+			}
+		}
+
+		if !slices.Equal([]uint64{0, 1}, numbers) {
+			t.Fatalf("wrong attempt numbers %v", numbers)
+		}
+
+		expectedCalls := []string{
+			"started 0",
+			"finished 0 false",
+			"scheduled 1 1ms",
+			"started 1",
+			"finished 1 true",
+		}
+
+		if !slices.Equal(expectedCalls, recorder.calls) {
+			t.Fatalf("wrong recorder calls, expected %v, actual %v", expectedCalls, recorder.calls)
+		}
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		var numbers []uint64
+
+		for attempt := range repeater.Attempts(context.Background(), repeater.ConstantProgression(0), 2) {
+			numbers = append(numbers, attempt.Number)
+		}
+
+		if !slices.Equal([]uint64{0, 1, 2}, numbers) {
+			t.Fatalf("wrong attempt numbers %v", numbers)
+		}
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &recorderMock{}
+
+		rp := repeater.New(repeater.ConstantProgression(0), repeater.WithRecorder(recorder))
+
+		for attempt := range rp.Attempts(context.Background(), 3) {
+			if attempt.Number == 1 {
+				attempt.Abort()
+			}
+		}
+
+		expectedCalls := []string{
+			"started 0",
+			"finished 0 false",
+			"scheduled 1 0s",
+			"started 1",
+			"finished 1 false",
+			"gave up 1 false",
+		}
+
+		if !slices.Equal(expectedCalls, recorder.calls) {
+			t.Fatalf("wrong recorder calls, expected %v, actual %v", expectedCalls, recorder.calls)
+		}
+	})
+
+	t.Run("break", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &recorderMock{}
+
+		rp := repeater.New(repeater.ConstantProgression(0), repeater.WithRecorder(recorder))
+
+		for range rp.Attempts(context.Background(), 3) {
+			break
+		}
+
+		expectedCalls := []string{
+			"started 0",
+			"finished 0 false",
+			"gave up 0 false",
+		}
+
+		if !slices.Equal(expectedCalls, recorder.calls) {
+			t.Fatalf("wrong recorder calls, expected %v, actual %v", expectedCalls, recorder.calls)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package repeater
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+type auditRecord struct {
+	Time      time.Time `json:"time"`
+	Event     string    `json:"event"`
+	Attempt   uint64    `json:"attempt"`
+	Elapsed   string    `json:"elapsed"`
+	Finished  *bool     `json:"finished,omitempty"`
+	Code      string    `json:"code,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Backoff   string    `json:"backoff,omitempty"`
+	Exhausted *bool     `json:"exhausted,omitempty"`
+	Stopped   bool      `json:"stopped,omitempty"`
+}
+
+// ErrorCoder is implemented by attempt errors carrying a code, e.g. an HTTP status
+type ErrorCoder interface {
+	ErrorCode() string
+}
+
+// WithAuditWriter appends one JSON object per line to w for every finished attempt,
+// scheduled retry and given up repeat. Records of attempts failed with an error, see RepeatErr,
+// hold the error and its code if the error wraps ErrorCoder. Writes are serialized, write errors are ignored.
+func WithAuditWriter(w io.Writer) Option {
+	return WithRecorder(&auditRecorder{enc: json.NewEncoder(w)})
+}
+
+type auditRecorder struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (a *auditRecorder) write(record auditRecord) {
+	record.Time = time.Now()
+
+	a.mu.Lock()
+	_ = a.enc.Encode(record)
+	a.mu.Unlock()
+}
+
+func (a *auditRecorder) AttemptStarted(context.Context, uint64, time.Duration) {}
+
+func (a *auditRecorder) AttemptFinished(_ context.Context, attempt uint64, elapsed time.Duration, finished bool) {
+	a.write(auditRecord{
+		Event:    AttemptFinished.String(),
+		Attempt:  attempt,
+		Elapsed:  elapsed.String(),
+		Finished: &finished,
+	})
+}
+
+func (a *auditRecorder) AttemptFailed(_ context.Context, attempt uint64, elapsed time.Duration, err error) {
+	finished := false
+
+	record := auditRecord{
+		Event:    AttemptFinished.String(),
+		Attempt:  attempt,
+		Elapsed:  elapsed.String(),
+		Finished: &finished,
+		Error:    err.Error(),
+	}
+
+	var coder ErrorCoder
+	if errors.As(err, &coder) {
+		record.Code = coder.ErrorCode()
+	}
+
+	a.write(record)
+}
+
+func (a *auditRecorder) RetryScheduled(_ context.Context, attempt uint64, elapsed, delay time.Duration) {
+	a.write(auditRecord{
+		Event:   RetryScheduled.String(),
+		Attempt: attempt,
+		Elapsed: elapsed.String(),
+		Backoff: delay.String(),
+	})
+}
+
+func (a *auditRecorder) GaveUp(_ context.Context, attempt uint64, elapsed time.Duration, exhausted bool) {
+	a.write(auditRecord{
+		Event:     RepeatFinished.String(),
+		Attempt:   attempt,
+		Elapsed:   elapsed.String(),
+		Exhausted: &exhausted,
+	})
+}
+
+func (a *auditRecorder) Stopped(_ context.Context, attempt uint64, elapsed time.Duration) {
+	a.write(auditRecord{
+		Event:   RepeatFinished.String(),
+		Attempt: attempt,
+		Elapsed: elapsed.String(),
+		Stopped: true,
+	})
+}
@@ -0,0 +1,129 @@
+package repeater_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_WithAuditWriter(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+
+	rp := repeater.New(
+		repeater.ConstantProgression(time.Millisecond),
+		repeater.WithAuditWriter(buf),
+	)
+
+	finished := rp.Repeat(func() bool { return false }, 1)
+	if finished {
+		t.Fatal("expected not finished repeat")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	expected := []map[string]any{
+		{"event": "attempt finished", "attempt": float64(0), "finished": false},
+		{"event": "retry scheduled", "attempt": float64(1), "backoff": "1ms"},
+		{"event": "attempt finished", "attempt": float64(1), "finished": false},
+		{"event": "repeat finished", "attempt": float64(1), "exhausted": true},
+	}
+
+	if len(lines) != len(expected) {
+		t.Fatalf("wrong audit lines count, expected %d, actual %d, output: %s", len(expected), len(lines), buf)
+	}
+
+	for i, line := range lines {
+		record := make(map[string]any)
+
+		err := json.Unmarshal([]byte(line), &record)
+		if err != nil {
+			t.Fatalf("unmarshal audit line %q: %s", line, err)
+		}
+
+		for _, key := range []string{"time", "elapsed"} {
+			if _, ok := record[key]; !ok {
+				t.Fatalf("audit line %q doesn't contain %s", line, key)
+			}
+
+			delete(record, key)
+		}
+
+		for key, value := range expected[i] {
+			if record[key] != value {
+				t.Fatalf("wrong %s in audit line %q, expected %v, actual %v", key, line, value, record[key])
+			}
+		}
+
+		if len(record) != len(expected[i]) {
+			t.Fatalf("unexpected keys in audit line %q", line)
+		}
+	}
+}
+
+type codeError struct {
+	code string
+}
+
+func (e codeError) Error() string {
+	return "code " + e.code
+}
+
+func (e codeError) ErrorCode() string {
+	return e.code
+}
+
+func Test_WithAuditWriter_AttemptError(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+
+	rp := repeater.New(
+		repeater.ConstantProgression(0),
+		repeater.WithAuditWriter(buf),
+	)
+
+	errs := []error{fmt.Errorf("unavailable: %w", codeError{code: "503"}), io.ErrUnexpectedEOF}
+
+	calls := 0
+
+	_ = rp.RepeatErr(context.Background(), func(context.Context) error {
+		calls++
+
+		return errs[calls-1]
+	}, 1)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	expected := []map[string]any{
+		{"event": "attempt finished", "finished": false, "code": "503", "error": "unavailable: code 503"},
+		{"event": "attempt finished", "finished": false, "error": "unexpected EOF"},
+	}
+
+	for i, line := range []string{lines[0], lines[2]} {
+		record := make(map[string]any)
+
+		err := json.Unmarshal([]byte(line), &record)
+		if err != nil {
+			t.Fatalf("unmarshal audit line %q: %s", line, err)
+		}
+
+		for key, value := range expected[i] {
+			if record[key] != value {
+				t.Fatalf("wrong %s in audit line %q, expected %v, actual %v", key, line, value, record[key])
+			}
+		}
+
+		if _, ok := record["code"]; ok && expected[i]["code"] == nil {
+			t.Fatalf("unexpected code in audit line %q", line)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package awsrepeater
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+// Retryer implements github.com/aws/aws-sdk-go-v2/aws.Retryer and aws.RetryerV2 on top of repeater.Policy,
+// the interfaces are satisfied structurally, so the module doesn't depend on the AWS SDK.
+// Use it with config.WithRetryer(func() aws.Retryer { return awsrepeater.New(policy, isRetryable) }).
+// The AWS client runs its own retry loop, so max elapsed of the policy is not used,
+// attempts, retries and not retryable errors are reported to the policy recorders and stats.
+// The SDK passes no attempt numbers and times to attempts, so recorders get zero attempt and elapsed for them,
+// repeats which used every attempt are not reported as given up
+type Retryer struct {
+	progression repeater.DurationProgression
+	retryCount  uint64
+	isRetryable func(err error) bool
+	recorder    repeater.Recorder
+}
+
+// New returns Retryer retrying errors accepted by isRetryable with the policy progression and retry count,
+// e.g. pass retry.IsErrorRetryables{...}.IsErrorRetryable from the SDK retry package
+func New(policy repeater.Policy, isRetryable func(err error) bool) *Retryer {
+	return &Retryer{
+		progression: policy.Repeater().Progression(),
+		retryCount:  policy.RetryCount(),
+		isRetryable: isRetryable,
+		recorder:    policy.Repeater().Recorder(),
+	}
+}
+
+// IsErrorRetryable is called by the SDK for every failed attempt, not retryable errors give up the repeat
+func (r *Retryer) IsErrorRetryable(err error) bool {
+	retryable := r.isRetryable(err)
+	if !retryable && err != nil {
+		r.recorder.GaveUp(context.Background(), 0, 0, false)
+	}
+
+	return retryable
+}
+
+// MaxAttempts includes the initial attempt, retry counts which don't fit int are truncated to math.MaxInt
+func (r *Retryer) MaxAttempts() int {
+	if r.retryCount >= math.MaxInt {
+		return math.MaxInt
+	}
+
+	return int(r.retryCount) + 1
+}
+
+// RetryDelay returns the delay after the failed attempt, attempts are counted from 1
+func (r *Retryer) RetryDelay(attempt int, _ error) (time.Duration, error) {
+	delay := r.progression.Duration(uint64(max(attempt-1, 0)))
+
+	r.recorder.RetryScheduled(context.Background(), uint64(max(attempt, 0)), 0, delay)
+
+	return delay, nil
+}
+
+// GetRetryToken never limits retries, the policy has no retry quota
+func (r *Retryer) GetRetryToken(context.Context, error) (releaseToken func(error) error, err error) {
+	return releaseNop, nil
+}
+
+// GetInitialToken is GetAttemptToken with the background context
+func (r *Retryer) GetInitialToken() (releaseToken func(error) error) {
+	releaseToken, _ = r.GetAttemptToken(context.Background())
+
+	return releaseToken
+}
+
+// GetAttemptToken is called by the SDK before every attempt, the token is released with the attempt error
+func (r *Retryer) GetAttemptToken(ctx context.Context) (releaseToken func(error) error, err error) {
+	r.recorder.AttemptStarted(ctx, 0, 0)
+
+	return func(err error) error {
+		r.recorder.AttemptFinished(ctx, 0, 0, err == nil)
+
+		return nil
+	}, nil
+}
+
+func releaseNop(error) error {
+	return nil
+}
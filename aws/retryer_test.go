@@ -0,0 +1,123 @@
+package awsrepeater_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	awsrepeater "github.com/amidgo/repeater/aws"
+)
+
+// awsRetryer is a copy of github.com/aws/aws-sdk-go-v2/aws.Retryer
+type awsRetryer interface {
+	IsErrorRetryable(error) bool
+	MaxAttempts() int
+	RetryDelay(attempt int, opErr error) (time.Duration, error)
+	GetRetryToken(ctx context.Context, opErr error) (releaseToken func(error) error, err error)
+	GetInitialToken() (releaseToken func(error) error)
+}
+
+// awsRetryerV2 is a copy of github.com/aws/aws-sdk-go-v2/aws.RetryerV2
+type awsRetryerV2 interface {
+	awsRetryer
+	GetAttemptToken(context.Context) (func(error) error, error)
+}
+
+var _ awsRetryerV2 = (*awsrepeater.Retryer)(nil)
+
+func Test_Retryer(t *testing.T) {
+	t.Parallel()
+
+	errThrottled := errors.New("throttled")
+
+	policy := repeater.NewPolicy(
+		repeater.WithProgression(repeater.NewArifmeticProgression(time.Second, time.Second)),
+		repeater.WithMaxRetries(3),
+	)
+
+	retryer := awsrepeater.New(policy, func(err error) bool { return errors.Is(err, errThrottled) })
+
+	if retryer.MaxAttempts() != 4 {
+		t.Fatalf("expected 4 max attempts, actual %d", retryer.MaxAttempts())
+	}
+
+	if !retryer.IsErrorRetryable(errThrottled) || retryer.IsErrorRetryable(errors.New("access denied")) {
+		t.Fatal("unexpected retryable errors")
+	}
+
+	for attempt, expected := range map[int]time.Duration{1: time.Second, 2: time.Second * 2, 3: time.Second * 3} {
+		delay, err := retryer.RetryDelay(attempt, errThrottled)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if delay != expected {
+			t.Fatalf("wrong delay of attempt %d, expected %s, actual %s", attempt, expected, delay)
+		}
+	}
+
+	release, err := retryer.GetRetryToken(context.Background(), errThrottled)
+	if err != nil || release(nil) != nil {
+		t.Fatalf("unexpected retry token error %v", err)
+	}
+}
+
+func Test_Retryer_MaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	retryer := awsrepeater.New(repeater.NewPolicy(repeater.WithMaxRetries(math.MaxUint64)), func(error) bool { return true })
+
+	if retryer.MaxAttempts() != math.MaxInt {
+		t.Fatalf("wrong max attempts, expected %d, actual %d", math.MaxInt, retryer.MaxAttempts())
+	}
+}
+
+func Test_Retryer_Stats(t *testing.T) {
+	t.Parallel()
+
+	errThrottled := errors.New("throttled")
+
+	policy := repeater.NewPolicy(
+		repeater.WithProgression(repeater.ConstantProgression(time.Second)),
+		repeater.WithMaxRetries(3),
+	)
+
+	retryer := awsrepeater.New(policy, func(err error) bool { return errors.Is(err, errThrottled) })
+
+	// the SDK retry loop of a call which succeeded on retry
+	release, _ := retryer.GetAttemptToken(context.Background())
+	_ = release(errThrottled)
+
+	if !retryer.IsErrorRetryable(errThrottled) {
+		t.Fatal("expected retryable error")
+	}
+
+	_, _ = retryer.RetryDelay(1, errThrottled)
+
+	release, _ = retryer.GetAttemptToken(context.Background())
+	_ = release(nil)
+
+	// the SDK retry loop of a call which failed with not retryable error
+	release = retryer.GetInitialToken()
+	_ = release(errors.New("access denied"))
+
+	if retryer.IsErrorRetryable(errors.New("access denied")) {
+		t.Fatal("unexpected retryable error")
+	}
+
+	expected := repeater.Stats{
+		Calls:      2,
+		Attempts:   3,
+		Successes:  1,
+		Aborts:     1,
+		TotalSleep: time.Second,
+	}
+
+	actual := policy.Repeater().Stats()
+	if expected != actual {
+		t.Fatalf("wrong stats, expected %+v, actual %+v", expected, actual)
+	}
+}
@@ -0,0 +1,45 @@
+package repeater
+
+import "context"
+
+type backoffKey struct{}
+
+// ContextWithBackoff returns ctx overriding the progression of every repeat called with it,
+// e.g. interactive requests use short backoffs while batch traffic keeps the policy default.
+// Delays set by the repeat func, e.g. with RepeatDelay, still take precedence
+func ContextWithBackoff(ctx context.Context, progression DurationProgression) context.Context {
+	return context.WithValue(ctx, backoffKey{}, progression)
+}
+
+// BackoffFromContext returns the progression of ContextWithBackoff
+func BackoffFromContext(ctx context.Context) (progression DurationProgression, ok bool) {
+	progression, ok = ctx.Value(backoffKey{}).(DurationProgression)
+
+	return progression, ok
+}
+
+// progressionFor returns the progression of ctx or the repeater progression
+func (r *Repeater) progressionFor(ctx context.Context) DurationProgression {
+	progression, ok := BackoffFromContext(ctx)
+	if ok && progression != nil {
+		return progression
+	}
+
+	return r.progression
+}
+
+type skipSleepKey struct{}
+
+// ContextWithoutSleep returns ctx making every repeat called with it skip the sleeps between attempts,
+// the repeat clock still advances by the skipped delays, so max elapsed and max total backoff
+// give up like with sleeping, e.g. to check a policy in tests without waiting its delays
+func ContextWithoutSleep(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipSleepKey{}, true)
+}
+
+// skipSleep reports whether ctx was returned by ContextWithoutSleep
+func skipSleep(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipSleepKey{}).(bool)
+
+	return skip
+}
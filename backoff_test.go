@@ -0,0 +1,78 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_ContextWithBackoff(t *testing.T) {
+	t.Parallel()
+
+	errTemporary := errors.New("temporary")
+
+	var delays []time.Duration
+
+	rp := repeater.New(repeater.ConstantProgression(time.Hour), repeater.WithRecorder(&delayRecorder{delays: &delays}))
+
+	ctx := repeater.ContextWithBackoff(context.Background(), repeater.ConstantProgression(time.Millisecond))
+
+	err := rp.RepeatErr(ctx, func(context.Context) error { return errTemporary }, 2)
+	if !errors.Is(err, errTemporary) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if len(delays) != 2 || delays[0] != time.Millisecond || delays[1] != time.Millisecond {
+		t.Fatalf("context backoff must override the repeater progression, actual delays %v", delays)
+	}
+
+	progression, ok := repeater.BackoffFromContext(ctx)
+	if !ok || progression != repeater.ConstantProgression(time.Millisecond) {
+		t.Fatalf("unexpected progression %v", progression)
+	}
+}
+
+type delayRecorder struct {
+	delays *[]time.Duration
+}
+
+func (d *delayRecorder) AttemptStarted(context.Context, uint64, time.Duration) {}
+
+func (d *delayRecorder) AttemptFinished(context.Context, uint64, time.Duration, bool) {}
+
+func (d *delayRecorder) RetryScheduled(_ context.Context, _ uint64, _, delay time.Duration) {
+	*d.delays = append(*d.delays, delay)
+}
+
+func (d *delayRecorder) GaveUp(context.Context, uint64, time.Duration, bool) {}
+
+func Test_ContextWithoutSleep(t *testing.T) {
+	t.Parallel()
+
+	rp := repeater.New(
+		repeater.ConstantProgression(time.Hour),
+		repeater.WithMaxElapsed(time.Hour*2+time.Minute),
+	)
+
+	start := time.Now()
+
+	err := rp.RepeatErr(repeater.ContextWithoutSleep(context.Background()), func(context.Context) error {
+		return errors.New("temporary")
+	}, 10)
+
+	if time.Since(start) > time.Second {
+		t.Fatalf("repeat slept %s", time.Since(start))
+	}
+
+	repeatErr := &repeater.Error{}
+	if !errors.As(err, &repeatErr) {
+		t.Fatalf("expected *repeater.Error, actual %v", err)
+	}
+
+	if repeatErr.Attempts != 3 || !repeatErr.Exceeded || repeatErr.Elapsed < time.Hour*2 {
+		t.Fatalf("unexpected error %+v", repeatErr)
+	}
+}
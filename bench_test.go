@@ -0,0 +1,92 @@
+package repeater_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/amidgo/repeater"
+)
+
+func Benchmark_Repeat_FirstAttemptSuccess(b *testing.B) {
+	rp := repeater.New(repeater.ConstantProgression(0))
+	rf := func() bool { return true }
+
+	b.ReportAllocs()
+
+	for range b.N {
+		rp.Repeat(rf, 3)
+	}
+}
+
+func Benchmark_RepeatContext_FirstAttemptSuccess(b *testing.B) {
+	rp := repeater.New(repeater.ConstantProgression(0))
+	ctx := context.Background()
+	rfctx := func(context.Context) bool { return true }
+
+	b.ReportAllocs()
+
+	for range b.N {
+		rp.RepeatContext(ctx, rfctx, 3)
+	}
+}
+
+func Benchmark_RepeatContext_ZeroBackoffRetries(b *testing.B) {
+	rp := repeater.New(repeater.ConstantProgression(0))
+	ctx := context.Background()
+	rfctx := func(context.Context) bool { return false }
+
+	b.ReportAllocs()
+
+	for range b.N {
+		rp.RepeatContext(ctx, rfctx, 3)
+	}
+}
+
+func Benchmark_RepeatContext_WithRecorders(b *testing.B) {
+	rp := repeater.New(
+		repeater.ConstantProgression(0),
+		repeater.WithRecorder(repeater.NewSlogRecorder(slog.New(slog.NewTextHandler(io.Discard, nil)))),
+		repeater.WithTimeline(),
+	)
+	ctx := context.Background()
+	rfctx := func(context.Context) bool { return false }
+
+	b.ReportAllocs()
+
+	for range b.N {
+		rp.RepeatContext(ctx, rfctx, 3)
+	}
+}
+
+func Benchmark_Policy_FirstAttemptSuccess(b *testing.B) {
+	policy := repeater.NewPolicy(repeater.WithMaxRetries(3))
+	rf := func() bool { return true }
+
+	b.ReportAllocs()
+
+	for range b.N {
+		policy.Repeat(rf)
+	}
+}
+
+func Test_Repeater_FirstAttemptSuccessAllocs(t *testing.T) {
+	rp := repeater.New(repeater.ConstantProgression(0))
+	ctx := context.Background()
+
+	rf := func() bool { return true }
+	rfctx := func(context.Context) bool { return true }
+
+	if allocs := testing.AllocsPerRun(100, func() { rp.Repeat(rf, 3) }); allocs != 0 {
+		t.Fatalf("Repeat allocates %v times on first attempt success", allocs)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { rp.RepeatContext(ctx, rfctx, 3) }); allocs != 0 {
+		t.Fatalf("RepeatContext allocates %v times on first attempt success", allocs)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { rp.RepeatContext(ctx, func(context.Context) bool { return false }, 3) }); allocs != 0 {
+		t.Fatalf("RepeatContext allocates %v times on zero backoff retries", allocs)
+	}
+}
@@ -0,0 +1,61 @@
+package repeater
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type budgetKey struct{}
+
+// retryBudget is the number of retries left for repeats sharing a context,
+// a nested budget takes retries from its parents too
+type retryBudget struct {
+	left   atomic.Int64
+	parent *retryBudget
+}
+
+// ContextWithBudget returns ctx limiting the total number of retries of all repeats called with it
+// or with contexts derived from it, e.g. every nested repeat made while serving one inbound request,
+// so retries don't multiply across service layers. Initial attempts are not counted, so every repeat calls
+// its func at least once. A repeat which runs out of the budget gives up with ErrBudgetExhausted cause.
+// A budget nested into another one is limited by both
+func ContextWithBudget(ctx context.Context, retries uint64) context.Context {
+	b := &retryBudget{}
+	b.left.Store(int64(min(retries, uint64(1<<63-1))))
+
+	b.parent, _ = ctx.Value(budgetKey{}).(*retryBudget)
+
+	return context.WithValue(ctx, budgetKey{}, b)
+}
+
+// BudgetFromContext returns the number of retries left in the budget of ctx
+func BudgetFromContext(ctx context.Context) (left uint64, ok bool) {
+	b, ok := ctx.Value(budgetKey{}).(*retryBudget)
+	if !ok {
+		return 0, false
+	}
+
+	// the budget is negative for a moment when a retry is taken from an exhausted one
+	return uint64(max(b.left.Load(), 0)), true
+}
+
+// takeBudget takes a retry from the budget of ctx, reports false if the budget is exhausted
+func takeBudget(ctx context.Context) bool {
+	b, ok := ctx.Value(budgetKey{}).(*retryBudget)
+	if !ok {
+		return true
+	}
+
+	for current := b; current != nil; current = current.parent {
+		if current.left.Add(-1) < 0 {
+			// return the retry to every budget it was taken from
+			for restore := b; restore != current.parent; restore = restore.parent {
+				restore.left.Add(1)
+			}
+
+			return false
+		}
+	}
+
+	return true
+}
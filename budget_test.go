@@ -0,0 +1,75 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_ContextWithBudget(t *testing.T) {
+	t.Parallel()
+
+	errTemporary := errors.New("temporary")
+
+	ctx := repeater.ContextWithBudget(context.Background(), 3)
+
+	calls := 0
+
+	fail := func(context.Context) error {
+		calls++
+
+		return errTemporary
+	}
+
+	err := repeater.RepeatErr(ctx, repeater.ConstantProgression(0), fail, 2)
+
+	repeatErr := &repeater.Error{}
+	if !errors.As(err, &repeatErr) || repeatErr.Attempts != 3 || !errors.Is(err, repeater.ErrRetryCountExceeded) {
+		t.Fatalf("expected retries exceeded after 3 attempts, actual %v", err)
+	}
+
+	err = repeater.RepeatErr(ctx, repeater.ConstantProgression(0), fail, 2)
+	if !errors.As(err, &repeatErr) || repeatErr.Attempts != 2 || !errors.Is(err, repeater.ErrBudgetExhausted) {
+		t.Fatalf("expected exhausted budget after 2 attempts, actual %v", err)
+	}
+
+	if !repeatErr.Exceeded {
+		t.Fatal("exhausted budget must be exceeded")
+	}
+
+	left, ok := repeater.BudgetFromContext(ctx)
+	if !ok || left != 0 {
+		t.Fatalf("wrong budget left, expected 0, actual %d", left)
+	}
+
+	if calls != 5 {
+		t.Fatalf("wrong calls, expected 5, actual %d", calls)
+	}
+}
+
+func Test_ContextWithBudget_Nested(t *testing.T) {
+	t.Parallel()
+
+	errTemporary := errors.New("temporary")
+
+	outer := repeater.ContextWithBudget(context.Background(), 1)
+	inner := repeater.ContextWithBudget(outer, 5)
+
+	calls := 0
+
+	err := repeater.RepeatErr(inner, repeater.ConstantProgression(0), func(context.Context) error {
+		calls++
+
+		return errTemporary
+	}, 5)
+	if !errors.Is(err, repeater.ErrBudgetExhausted) || calls != 2 {
+		t.Fatalf("expected exhausted outer budget after 2 calls, actual %v after %d calls", err, calls)
+	}
+
+	left, _ := repeater.BudgetFromContext(inner)
+	if left != 4 {
+		t.Fatalf("retry refused by the outer budget must be returned to the inner one, actual %d left", left)
+	}
+}
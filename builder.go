@@ -0,0 +1,79 @@
+package repeater
+
+import "time"
+
+// BackoffBuilder composes a progression with a cap and a jitter, e.g.
+//
+//	progression, err := repeater.NewBackoffBuilder().
+//		Exponential(100*time.Millisecond, 2).
+//		Cap(10 * time.Second).
+//		Jitter(0.2).
+//		Build()
+//
+// Parameters are validated by Build like Config.Validate, the cap is applied before the jitter
+type BackoffBuilder struct {
+	config Config
+}
+
+func NewBackoffBuilder() BackoffBuilder {
+	return BackoffBuilder{}
+}
+
+func (b BackoffBuilder) Constant(d time.Duration) BackoffBuilder {
+	b.config.Backoff, b.config.Initial = ConstantBackoff, d
+
+	return b
+}
+
+func (b BackoffBuilder) Arifmetic(initial, delta time.Duration) BackoffBuilder {
+	b.config.Backoff, b.config.Initial, b.config.Delta = ArifmeticBackoff, initial, delta
+
+	return b
+}
+
+func (b BackoffBuilder) Fibonacci(d time.Duration) BackoffBuilder {
+	b.config.Backoff, b.config.Initial = FibonacciBackoff, d
+
+	return b
+}
+
+func (b BackoffBuilder) Exponential(initial time.Duration, factor float64) BackoffBuilder {
+	b.config.Backoff, b.config.Initial, b.config.Factor = ExponentialBackoff, initial, factor
+
+	return b
+}
+
+// Cap limits every duration, zero means no limit
+func (b BackoffBuilder) Cap(limit time.Duration) BackoffBuilder {
+	b.config.Cap = limit
+
+	return b
+}
+
+// Jitter randomizes durations by factor in [0, 1] range, zero disables jitter
+func (b BackoffBuilder) Jitter(factor float64) BackoffBuilder {
+	b.config.Jitter = factor
+
+	return b
+}
+
+// Config returns the builder settings, e.g. for MaxRetries and Config.Build
+func (b BackoffBuilder) Config() Config {
+	return b.config
+}
+
+// Build validates the settings and returns the progression,
+// the error wraps ErrInvalidConfig or ErrUnknownBackoffKind if no backoff was chosen
+func (b BackoffBuilder) Build() (DurationProgression, error) {
+	err := b.config.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	progression, err := b.config.progression()
+	if err != nil {
+		return nil, err
+	}
+
+	return b.config.limit(progression), nil
+}
@@ -0,0 +1,54 @@
+package repeater_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_BackoffBuilder(t *testing.T) {
+	t.Parallel()
+
+	progression, err := repeater.NewBackoffBuilder().
+		Exponential(100*time.Millisecond, 2).
+		Cap(time.Second).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for attempt, expected := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second} {
+		d := progression.Duration(uint64(attempt))
+		if d != expected {
+			t.Fatalf("wrong duration of attempt %d, expected %s, actual %s", attempt, expected, d)
+		}
+	}
+
+	progression, err = repeater.NewBackoffBuilder().Constant(time.Second).Jitter(0.2).Build()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for attempt := range uint64(10) {
+		d := progression.Duration(attempt)
+		if d < 800*time.Millisecond || d > 1200*time.Millisecond {
+			t.Fatalf("jittered duration %s out of range", d)
+		}
+	}
+}
+
+func Test_BackoffBuilder_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := repeater.NewBackoffBuilder().Exponential(time.Second, 2).Jitter(2).Build()
+	if !errors.Is(err, repeater.ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, actual %v", err)
+	}
+
+	_, err = repeater.NewBackoffBuilder().Cap(time.Second).Build()
+	if !errors.Is(err, repeater.ErrUnknownBackoffKind) {
+		t.Fatalf("expected ErrUnknownBackoffKind, actual %v", err)
+	}
+}
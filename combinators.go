@@ -0,0 +1,84 @@
+package repeater
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Op is a repeat func with its own policy used by All and Any
+type Op struct {
+	Policy Policy
+	Func   RepeatErrFunc
+}
+
+// All repeats ops concurrently and returns nil when every op finished or stopped with a Stop error.
+// The first op which gave up cancels the others and its *Error is returned
+func All(ctx context.Context, ops ...Op) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+
+	for _, op := range ops {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			err := op.Policy.RepeatErr(ctx, op.Func)
+			if err == nil || errors.Is(err, ErrStopped) {
+				return
+			}
+
+			once.Do(func() {
+				firstErr = err
+
+				cancel()
+			})
+		}()
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
+
+// Any repeats ops concurrently and returns nil when the first op finished, the others are canceled.
+// If every op gave up, their joined *Error are returned in the order of ops, Any without ops returns nil
+func Any(ctx context.Context, ops ...Op) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(ops))
+	)
+
+	for i, op := range ops {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			errs[i] = op.Policy.RepeatErr(ctx, op.Func)
+			if errs[i] == nil {
+				cancel()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,119 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func blockingOp(policy repeater.Policy) repeater.Op {
+	return repeater.Op{
+		Policy: policy,
+		Func: func(ctx context.Context) error {
+			<-ctx.Done()
+
+			return ctx.Err()
+		},
+	}
+}
+
+func Test_All(t *testing.T) {
+	t.Parallel()
+
+	policy := repeater.NewPolicy(
+		repeater.WithProgression(repeater.ConstantProgression(time.Millisecond)),
+		repeater.WithMaxRetries(2),
+	)
+
+	t.Run("all finished", func(t *testing.T) {
+		t.Parallel()
+
+		err := repeater.All(context.Background(),
+			repeater.Op{Policy: policy, Func: func(context.Context) error { return nil }},
+			repeater.Op{Policy: policy, Func: func(context.Context) error { return nil }},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("one gave up", func(t *testing.T) {
+		t.Parallel()
+
+		err := repeater.All(context.Background(),
+			blockingOp(policy),
+			repeater.Op{Policy: policy, Func: func(context.Context) error { return io.ErrUnexpectedEOF }},
+		)
+
+		repeatErr := &repeater.Error{}
+		if !errors.As(err, &repeatErr) {
+			t.Fatalf("expected *repeater.Error, actual %v", err)
+		}
+
+		if !errors.Is(err, io.ErrUnexpectedEOF) || repeatErr.Attempts != 3 {
+			t.Fatalf("expected error of the op which gave up, actual %v", err)
+		}
+	})
+
+	t.Run("one stopped", func(t *testing.T) {
+		t.Parallel()
+
+		var finished bool
+
+		err := repeater.All(context.Background(),
+			repeater.Op{Policy: policy, Func: func(context.Context) error { return repeater.Stop() }},
+			repeater.Op{Policy: policy, Func: func(ctx context.Context) error {
+				time.Sleep(time.Millisecond * 10)
+
+				finished = ctx.Err() == nil
+
+				return nil
+			}},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if !finished {
+			t.Fatal("stopped op canceled the others")
+		}
+	})
+}
+
+func Test_Any(t *testing.T) {
+	t.Parallel()
+
+	policy := repeater.NewPolicy(
+		repeater.WithProgression(repeater.ConstantProgression(time.Millisecond)),
+		repeater.WithMaxRetries(2),
+	)
+
+	t.Run("first finished", func(t *testing.T) {
+		t.Parallel()
+
+		err := repeater.Any(context.Background(),
+			blockingOp(policy),
+			repeater.Op{Policy: policy, Func: func(context.Context) error { return nil }},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("all gave up", func(t *testing.T) {
+		t.Parallel()
+
+		err := repeater.Any(context.Background(),
+			repeater.Op{Policy: policy, Func: func(context.Context) error { return io.ErrUnexpectedEOF }},
+			repeater.Op{Policy: policy, Func: func(context.Context) error { return io.ErrClosedPipe }},
+		)
+
+		if !errors.Is(err, io.ErrUnexpectedEOF) || !errors.Is(err, io.ErrClosedPipe) {
+			t.Fatalf("expected joined errors of both ops, actual %v", err)
+		}
+	})
+}
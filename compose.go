@@ -0,0 +1,134 @@
+package repeater
+
+import (
+	"context"
+	"time"
+)
+
+// CircuitBreaker rejects calls while it is open, Allow returns a non-nil err rejecting the call,
+// otherwise done must be called with the result of the allowed call
+type CircuitBreaker interface {
+	Allow() (done func(err error), err error)
+}
+
+// RateLimitFunc blocks until the call may proceed, e.g. (*rate.Limiter).Wait
+type RateLimitFunc func(ctx context.Context) error
+
+// FallbackFunc replaces the error of a failed execution, returning nil recovers it
+type FallbackFunc func(ctx context.Context, err error) error
+
+// Composer layers independent policies into an Executor, the zero value is ready to use.
+// Layers are evaluated in a fixed order regardless of the order they are set in, from outer to inner:
+//
+//	fallback -> retry -> circuit breaker -> rate limit -> attempt timeout -> func
+//
+// so every retry passes the circuit breaker, takes a rate limit permit and has its own timeout,
+// while the fallback sees the error of the whole repeat. Unset layers are skipped
+type Composer struct {
+	fallback  FallbackFunc
+	retry     *Policy
+	breaker   CircuitBreaker
+	rateLimit RateLimitFunc
+	timeout   time.Duration
+}
+
+// Fallback sets the outermost layer handling the error of the execution
+func (c *Composer) Fallback(fallback FallbackFunc) *Composer {
+	c.fallback = fallback
+
+	return c
+}
+
+// Retry repeats the inner layers with policy, errors rejecting the call by the circuit breaker are retried,
+// rate limit errors abort the repeat
+func (c *Composer) Retry(policy Policy) *Composer {
+	c.retry = &policy
+
+	return c
+}
+
+// CircuitBreaker sets the breaker guarding every attempt
+func (c *Composer) CircuitBreaker(breaker CircuitBreaker) *Composer {
+	c.breaker = breaker
+
+	return c
+}
+
+// RateLimit sets the limiter every attempt waits for after passing the circuit breaker
+func (c *Composer) RateLimit(rateLimit RateLimitFunc) *Composer {
+	c.rateLimit = rateLimit
+
+	return c
+}
+
+// Timeout limits the duration of every attempt, zero disables it
+func (c *Composer) Timeout(timeout time.Duration) *Composer {
+	c.timeout = timeout
+
+	return c
+}
+
+// Executor returns the composed layers, later changes of the composer don't affect it
+func (c *Composer) Executor() Executor {
+	return Executor{layers: *c}
+}
+
+// Executor runs funcs through the layers of a Composer, it is safe for concurrent use
+// if the circuit breaker and the rate limiter are
+type Executor struct {
+	layers Composer
+}
+
+// Run calls f through the composed layers, the returned error is the fallback result,
+// *Error if the retry layer gave up, or the error of the single attempt
+func (e Executor) Run(ctx context.Context, f RepeatErrFunc) error {
+	attempt := e.attempt(f)
+
+	var err error
+
+	if e.layers.retry != nil {
+		err = e.layers.retry.RepeatErr(ctx, attempt)
+	} else {
+		err = attempt(ctx)
+	}
+
+	if err != nil && e.layers.fallback != nil {
+		return e.layers.fallback(ctx, err)
+	}
+
+	return err
+}
+
+// attempt wraps f with the per attempt layers
+func (e Executor) attempt(f RepeatErrFunc) RepeatErrFunc {
+	layers := e.layers
+
+	return func(ctx context.Context) (err error) {
+		if layers.breaker != nil {
+			done, allowErr := layers.breaker.Allow()
+			if allowErr != nil {
+				return allowErr
+			}
+
+			defer func() {
+				done(err)
+			}()
+		}
+
+		if layers.rateLimit != nil {
+			err = layers.rateLimit(ctx)
+			if err != nil {
+				return Abort(err)
+			}
+		}
+
+		if layers.timeout > 0 {
+			var cancel context.CancelFunc
+
+			ctx, cancel = context.WithTimeout(ctx, layers.timeout)
+			defer cancel()
+		}
+
+		return f(ctx)
+	}
+}
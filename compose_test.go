@@ -0,0 +1,126 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+type recordingBreaker struct {
+	events *[]string
+	open   bool
+}
+
+func (b *recordingBreaker) Allow() (func(err error), error) {
+	*b.events = append(*b.events, "breaker")
+
+	if b.open {
+		b.open = false
+
+		return nil, errors.New("breaker is open")
+	}
+
+	return func(err error) {
+		*b.events = append(*b.events, "breaker done: "+errString(err))
+	}, nil
+}
+
+func errString(err error) string {
+	if err == nil {
+		return "ok"
+	}
+
+	return err.Error()
+}
+
+func Test_Composer(t *testing.T) {
+	t.Parallel()
+
+	var events []string
+
+	errTemporary := errors.New("temporary")
+
+	composer := &repeater.Composer{}
+
+	executor := composer.
+		Timeout(time.Second).
+		RateLimit(func(context.Context) error {
+			events = append(events, "rate limit")
+
+			return nil
+		}).
+		CircuitBreaker(&recordingBreaker{events: &events, open: true}).
+		Retry(repeater.NewPolicy(repeater.WithMaxRetries(3))).
+		Executor()
+
+	calls := 0
+
+	err := executor.Run(context.Background(), func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("attempt has no timeout")
+		}
+
+		calls++
+		events = append(events, "call")
+
+		if calls == 1 {
+			return errTemporary
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := []string{
+		"breaker",
+		"breaker", "rate limit", "call", "breaker done: temporary",
+		"breaker", "rate limit", "call", "breaker done: ok",
+	}
+
+	if !slices.Equal(expected, events) {
+		t.Fatalf("wrong events, expected %q, actual %q", expected, events)
+	}
+}
+
+func Test_Composer_Fallback(t *testing.T) {
+	t.Parallel()
+
+	errLimit := errors.New("limit exceeded")
+
+	calls := 0
+
+	executor := (&repeater.Composer{}).
+		Retry(repeater.NewPolicy(repeater.WithMaxRetries(3))).
+		RateLimit(func(context.Context) error {
+			calls++
+
+			return errLimit
+		}).
+		Fallback(func(_ context.Context, err error) error {
+			if !errors.Is(err, errLimit) {
+				t.Errorf("unexpected fallback error %v", err)
+			}
+
+			return nil
+		}).
+		Executor()
+
+	err := executor.Run(context.Background(), func(context.Context) error {
+		t.Error("rate limited func must not be called")
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("rate limit error must abort the retry, actual %d calls", calls)
+	}
+}
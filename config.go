@@ -0,0 +1,267 @@
+package repeater
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownBackoffKind = errors.New("unknown backoff kind")
+	ErrInvalidConfig      = errors.New("invalid config")
+)
+
+type BackoffKind string
+
+const (
+	ConstantBackoff    BackoffKind = "constant"
+	ArifmeticBackoff   BackoffKind = "arifmetic"
+	FibonacciBackoff   BackoffKind = "fibonacci"
+	ExponentialBackoff BackoffKind = "exponential"
+)
+
+// Config describes a Policy in service configuration files,
+// durations are encoded as strings parsed by time.ParseDuration
+type Config struct {
+	Backoff BackoffKind `json:"backoff" yaml:"backoff"`
+	// initial duration for every backoff kind
+	Initial time.Duration `json:"initial" yaml:"initial"`
+	// arifmetic backoff delta
+	Delta time.Duration `json:"delta,omitempty" yaml:"delta,omitempty"`
+	// exponential backoff factor
+	Factor float64 `json:"factor,omitempty" yaml:"factor,omitempty"`
+	// max backoff duration, zero means no limit
+	Cap time.Duration `json:"cap,omitempty" yaml:"cap,omitempty"`
+	// jitter factor in [0, 1] range, zero disables jitter
+	Jitter     float64       `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+	MaxRetries uint64        `json:"max_retries" yaml:"max_retries"`
+	MaxElapsed time.Duration `json:"max_elapsed,omitempty" yaml:"max_elapsed,omitempty"`
+}
+
+// Validate rejects configs which can't produce a sensible policy
+func (c Config) Validate() error {
+	_, err := c.progression()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	if c.Initial < 0 {
+		errs = append(errs, fmt.Errorf("negative initial %s", c.Initial))
+	}
+
+	if c.Backoff == ArifmeticBackoff && c.Delta < 0 {
+		errs = append(errs, fmt.Errorf("negative delta %s makes later backoffs negative", c.Delta))
+	}
+
+	if c.Backoff == ExponentialBackoff {
+		switch {
+		case c.Factor <= 0:
+			errs = append(errs, fmt.Errorf("exponential factor %g must be positive", c.Factor))
+		case c.Factor < 1 && c.Cap > 0:
+			errs = append(errs, fmt.Errorf("exponential factor %g is less than 1, cap %s is never reached", c.Factor, c.Cap))
+		}
+	}
+
+	switch {
+	case c.Cap < 0:
+		errs = append(errs, fmt.Errorf("negative cap %s", c.Cap))
+	case c.Cap > 0 && c.Cap < c.Initial:
+		errs = append(errs, fmt.Errorf("cap %s is below initial %s", c.Cap, c.Initial))
+	}
+
+	if c.Jitter < 0 || c.Jitter > 1 {
+		errs = append(errs, fmt.Errorf("jitter %g out of [0, 1] range", c.Jitter))
+	}
+
+	if c.MaxElapsed < 0 {
+		errs = append(errs, fmt.Errorf("negative max_elapsed %s", c.MaxElapsed))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// Build validates config and constructs Policy from it, opts are passed to New
+func (c Config) Build(opts ...Option) (Policy, error) {
+	err := c.Validate()
+	if err != nil {
+		return Policy{}, err
+	}
+
+	progression, err := c.progression()
+	if err != nil {
+		return Policy{}, err
+	}
+
+	progression = c.limit(progression)
+
+	if c.MaxElapsed > 0 {
+		opts = append([]Option{WithMaxElapsed(c.MaxElapsed)}, opts...)
+	}
+
+	return Policy{
+		repeater:   New(progression, opts...),
+		retryCount: c.MaxRetries,
+	}, nil
+}
+
+func (c Config) progression() (DurationProgression, error) {
+	switch c.Backoff {
+	case ConstantBackoff:
+		return ConstantProgression(c.Initial), nil
+	case ArifmeticBackoff:
+		return NewArifmeticProgression(c.Initial, c.Delta), nil
+	case FibonacciBackoff:
+		return FibonacciProgression(c.Initial), nil
+	case ExponentialBackoff:
+		return NewExponentialProgression(c.Initial, c.Factor), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownBackoffKind, c.Backoff)
+	}
+}
+
+// limit wraps progression with the cap and the jitter of config
+func (c Config) limit(progression DurationProgression) DurationProgression {
+	if c.Cap > 0 {
+		progression = NewCappedProgression(progression, c.Cap)
+	}
+
+	if c.Jitter > 0 {
+		progression = NewJitterProgression(progression, c.Jitter)
+	}
+
+	return progression
+}
+
+type configJSON struct {
+	Backoff    BackoffKind `json:"backoff"`
+	Initial    string      `json:"initial"`
+	Delta      string      `json:"delta,omitempty"`
+	Factor     float64     `json:"factor,omitempty"`
+	Cap        string      `json:"cap,omitempty"`
+	Jitter     float64     `json:"jitter,omitempty"`
+	MaxRetries uint64      `json:"max_retries"`
+	MaxElapsed string      `json:"max_elapsed,omitempty"`
+}
+
+func (c Config) MarshalJSON() ([]byte, error) {
+	return json.Marshal(configJSON{
+		Backoff:    c.Backoff,
+		Initial:    c.Initial.String(),
+		Delta:      formatOptionalDuration(c.Delta),
+		Factor:     c.Factor,
+		Cap:        formatOptionalDuration(c.Cap),
+		Jitter:     c.Jitter,
+		MaxRetries: c.MaxRetries,
+		MaxElapsed: formatOptionalDuration(c.MaxElapsed),
+	})
+}
+
+func (c *Config) UnmarshalJSON(data []byte) error {
+	var cj configJSON
+
+	err := json.Unmarshal(data, &cj)
+	if err != nil {
+		return err
+	}
+
+	cfg := Config{
+		Backoff:    cj.Backoff,
+		Factor:     cj.Factor,
+		Jitter:     cj.Jitter,
+		MaxRetries: cj.MaxRetries,
+	}
+
+	for _, d := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"initial", cj.Initial, &cfg.Initial},
+		{"delta", cj.Delta, &cfg.Delta},
+		{"cap", cj.Cap, &cfg.Cap},
+		{"max_elapsed", cj.MaxElapsed, &cfg.MaxElapsed},
+	} {
+		err = parseOptionalDuration(d.value, d.dst)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidConfig, d.name, err)
+		}
+	}
+
+	*c = cfg
+
+	return nil
+}
+
+// UnmarshalText parses space separated key=value pairs with json field names, e.g.
+// "backoff=exponential initial=100ms factor=2 cap=10s max_retries=5"
+func (c *Config) UnmarshalText(text []byte) error {
+	var cfg Config
+
+	for _, field := range strings.Fields(string(text)) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return fmt.Errorf("%w: field %q isn't key=value pair", ErrInvalidConfig, field)
+		}
+
+		err := cfg.set(key, value)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidConfig, key, err)
+		}
+	}
+
+	*c = cfg
+
+	return nil
+}
+
+func (c *Config) set(key, value string) (err error) {
+	switch key {
+	case "backoff":
+		c.Backoff = BackoffKind(value)
+	case "initial":
+		c.Initial, err = time.ParseDuration(value)
+	case "delta":
+		c.Delta, err = time.ParseDuration(value)
+	case "factor":
+		c.Factor, err = strconv.ParseFloat(value, 64)
+	case "cap":
+		c.Cap, err = time.ParseDuration(value)
+	case "jitter":
+		c.Jitter, err = strconv.ParseFloat(value, 64)
+	case "max_retries":
+		c.MaxRetries, err = strconv.ParseUint(value, 10, 64)
+	case "max_elapsed":
+		c.MaxElapsed, err = time.ParseDuration(value)
+	default:
+		return errors.New("unknown key")
+	}
+
+	return err
+}
+
+func formatOptionalDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+
+	return d.String()
+}
+
+func parseOptionalDuration(s string, dst *time.Duration) (err error) {
+	if s == "" {
+		return nil
+	}
+
+	*dst, err = time.ParseDuration(s)
+
+	return err
+}
@@ -0,0 +1,294 @@
+package repeater_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Config_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	data := `{
+		"backoff": "exponential",
+		"initial": "100ms",
+		"factor": 2,
+		"cap": "10s",
+		"jitter": 0.2,
+		"max_retries": 5,
+		"max_elapsed": "1m"
+	}`
+
+	var cfg repeater.Config
+
+	err := json.Unmarshal([]byte(data), &cfg)
+	if err != nil {
+		t.Fatalf("unmarshal config: %s", err)
+	}
+
+	expected := repeater.Config{
+		Backoff:    repeater.ExponentialBackoff,
+		Initial:    time.Millisecond * 100,
+		Factor:     2,
+		Cap:        time.Second * 10,
+		Jitter:     0.2,
+		MaxRetries: 5,
+		MaxElapsed: time.Minute,
+	}
+
+	if expected != cfg {
+		t.Fatalf("wrong config, expected %+v, actual %+v", expected, cfg)
+	}
+
+	marshaled, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal config: %s", err)
+	}
+
+	var roundTrip repeater.Config
+
+	err = json.Unmarshal(marshaled, &roundTrip)
+	if err != nil {
+		t.Fatalf("unmarshal marshaled config: %s", err)
+	}
+
+	if expected != roundTrip {
+		t.Fatalf("wrong round trip config, expected %+v, actual %+v", expected, roundTrip)
+	}
+}
+
+func Test_Config_UnmarshalJSON_InvalidDuration(t *testing.T) {
+	t.Parallel()
+
+	var cfg repeater.Config
+
+	err := json.Unmarshal([]byte(`{"backoff": "constant", "initial": "second"}`), &cfg)
+	if !errors.Is(err, repeater.ErrInvalidConfig) {
+		t.Fatalf("wrong error, expected %s, actual %v", repeater.ErrInvalidConfig, err)
+	}
+}
+
+func Test_Config_UnmarshalText(t *testing.T) {
+	t.Parallel()
+
+	var cfg repeater.Config
+
+	err := cfg.UnmarshalText([]byte("backoff=arifmetic initial=1s delta=500ms max_retries=3"))
+	if err != nil {
+		t.Fatalf("unmarshal config: %s", err)
+	}
+
+	expected := repeater.Config{
+		Backoff:    repeater.ArifmeticBackoff,
+		Initial:    time.Second,
+		Delta:      time.Millisecond * 500,
+		MaxRetries: 3,
+	}
+
+	if expected != cfg {
+		t.Fatalf("wrong config, expected %+v, actual %+v", expected, cfg)
+	}
+
+	for _, text := range []string{"backoff", "unknown=1", "initial=1"} {
+		err = cfg.UnmarshalText([]byte(text))
+		if !errors.Is(err, repeater.ErrInvalidConfig) {
+			t.Fatalf("wrong error for %q, expected %s, actual %v", text, repeater.ErrInvalidConfig, err)
+		}
+	}
+}
+
+func Test_Config_Build(t *testing.T) {
+	t.Parallel()
+
+	cfg := repeater.Config{
+		Backoff:    repeater.ConstantBackoff,
+		Initial:    time.Millisecond,
+		MaxRetries: 2,
+	}
+
+	policy, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("build policy: %s", err)
+	}
+
+	if policy.RetryCount() != 2 {
+		t.Fatalf("wrong retry count, expected 2, actual %d", policy.RetryCount())
+	}
+
+	calls := 0
+
+	finished := policy.Repeat(func() bool {
+		calls++
+
+		return false
+	})
+	if finished {
+		t.Fatal("expected not finished repeat")
+	}
+
+	if calls != 3 {
+		t.Fatalf("wrong calls count, expected 3, actual %d", calls)
+	}
+
+	_, err = repeater.Config{Backoff: "linear"}.Build()
+	if !errors.Is(err, repeater.ErrUnknownBackoffKind) {
+		t.Fatalf("wrong error, expected %s, actual %v", repeater.ErrUnknownBackoffKind, err)
+	}
+}
+
+func Test_Config_Build_MaxElapsed(t *testing.T) {
+	t.Parallel()
+
+	cfg := repeater.Config{
+		Backoff:    repeater.ConstantBackoff,
+		Initial:    time.Millisecond * 20,
+		MaxRetries: 10,
+		MaxElapsed: time.Millisecond * 50,
+	}
+
+	policy, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("build policy: %s", err)
+	}
+
+	calls := 0
+
+	finished := policy.Repeat(func() bool {
+		calls++
+
+		return false
+	})
+	if finished {
+		t.Fatal("expected not finished repeat")
+	}
+
+	if calls != 3 {
+		t.Fatalf("wrong calls count, expected 3, actual %d", calls)
+	}
+
+	stats := policy.Repeater().Stats()
+	if stats.Exceeded != 1 {
+		t.Fatalf("wrong exceeded count, expected 1, actual %d", stats.Exceeded)
+	}
+}
+
+func Test_Config_Validate(t *testing.T) {
+	t.Parallel()
+
+	valid := repeater.Config{
+		Backoff:    repeater.ExponentialBackoff,
+		Initial:    time.Millisecond * 100,
+		Factor:     2,
+		Cap:        time.Second,
+		Jitter:     0.5,
+		MaxRetries: 3,
+	}
+
+	err := valid.Validate()
+	if err != nil {
+		t.Fatalf("unexpected validation error: %s", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		cfg  repeater.Config
+	}{
+		{"negative initial", repeater.Config{Backoff: repeater.ConstantBackoff, Initial: -time.Second}},
+		{"negative arifmetic", repeater.Config{Backoff: repeater.ArifmeticBackoff, Initial: time.Second, Delta: -time.Second * 2}},
+		{"negative arifmetic delta", repeater.Config{Backoff: repeater.ArifmeticBackoff, Initial: time.Second * 10, Delta: -time.Second}},
+		{"zero factor", repeater.Config{Backoff: repeater.ExponentialBackoff, Initial: time.Second}},
+		{"factor less than one with cap", repeater.Config{Backoff: repeater.ExponentialBackoff, Initial: time.Second, Factor: 0.5, Cap: time.Second}},
+		{"negative cap", repeater.Config{Backoff: repeater.ConstantBackoff, Initial: time.Second, Cap: -time.Second}},
+		{"cap below initial", repeater.Config{Backoff: repeater.ConstantBackoff, Initial: time.Second, Cap: time.Millisecond}},
+		{"jitter above one", repeater.Config{Backoff: repeater.ConstantBackoff, Initial: time.Second, Jitter: 1.5}},
+		{"negative max elapsed", repeater.Config{Backoff: repeater.ConstantBackoff, Initial: time.Second, MaxElapsed: -time.Second}},
+	} {
+		err := tc.cfg.Validate()
+		if !errors.Is(err, repeater.ErrInvalidConfig) {
+			t.Fatalf("%s: wrong error, expected %s, actual %v", tc.name, repeater.ErrInvalidConfig, err)
+		}
+
+		_, err = tc.cfg.Build()
+		if !errors.Is(err, repeater.ErrInvalidConfig) {
+			t.Fatalf("%s: Build must validate config, actual error %v", tc.name, err)
+		}
+	}
+
+	err = repeater.Config{Backoff: "linear"}.Validate()
+	if !errors.Is(err, repeater.ErrUnknownBackoffKind) {
+		t.Fatalf("wrong error, expected %s, actual %v", repeater.ErrUnknownBackoffKind, err)
+	}
+}
+
+func Test_Policy_Validate(t *testing.T) {
+	t.Parallel()
+
+	err := repeater.Policy{}.Validate()
+	if !errors.Is(err, repeater.ErrInvalidConfig) {
+		t.Fatalf("wrong error, expected %s, actual %v", repeater.ErrInvalidConfig, err)
+	}
+
+	err = repeater.NewPolicy().Validate()
+	if err != nil {
+		t.Fatalf("unexpected validation error: %s", err)
+	}
+
+	valid := repeater.NewPolicy(
+		repeater.WithProgression(repeater.NewJitterProgression(
+			repeater.NewCappedProgression(repeater.NewExponentialProgression(time.Millisecond*100, 2), time.Second),
+			0.5,
+		)),
+		repeater.WithMaxRetries(3),
+		repeater.WithRepeaterOptions(repeater.WithMaxElapsed(time.Minute)),
+	)
+
+	err = valid.Validate()
+	if err != nil {
+		t.Fatalf("unexpected validation error: %s", err)
+	}
+
+	for _, tc := range []struct {
+		name        string
+		progression repeater.DurationProgression
+		opts        []repeater.Option
+	}{
+		{"negative constant", repeater.ConstantProgression(-time.Second), nil},
+		{"negative fibonacci", repeater.FibonacciProgression(-time.Second), nil},
+		{"negative arifmetic initial", repeater.NewArifmeticProgression(-time.Second, time.Second*2), nil},
+		{"negative arifmetic", repeater.NewArifmeticProgression(time.Second, -time.Second*2), nil},
+		{"negative arifmetic delta", repeater.NewArifmeticProgression(time.Second*10, -time.Second), nil},
+		{"negative exponential initial", repeater.NewExponentialProgression(-time.Second, 2), nil},
+		{"zero factor", repeater.NewExponentialProgression(time.Second, 0), nil},
+		{
+			"factor less than one with cap",
+			repeater.NewCappedProgression(repeater.NewExponentialProgression(time.Second, 0.5), time.Second),
+			nil,
+		},
+		{"cap below initial", repeater.NewCappedProgression(repeater.ConstantProgression(time.Second), time.Millisecond), nil},
+		{"jitter above one", repeater.NewJitterProgression(repeater.ConstantProgression(time.Second), 1.5), nil},
+		{
+			"negative jittered",
+			repeater.NewJitterProgression(repeater.ConstantProgression(-time.Second), 0.5),
+			nil,
+		},
+		{"negative max elapsed", repeater.ConstantProgression(time.Second), []repeater.Option{repeater.WithMaxElapsed(-time.Second)}},
+		{
+			"negative max total backoff",
+			repeater.ConstantProgression(time.Second),
+			[]repeater.Option{repeater.WithMaxTotalBackoff(-time.Second)},
+		},
+	} {
+		policy := repeater.NewPolicy(
+			repeater.WithProgression(tc.progression),
+			repeater.WithRepeaterOptions(tc.opts...),
+		)
+
+		err := policy.Validate()
+		if !errors.Is(err, repeater.ErrInvalidConfig) {
+			t.Fatalf("%s: wrong error, expected %s, actual %v", tc.name, repeater.ErrInvalidConfig, err)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package repeater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ErrNotConnected is returned by ConnectionManager.Get with WithFailFast while the connection is being established
+var ErrNotConnected = errors.New("not connected")
+
+type connectionOptions struct {
+	failFast bool
+}
+
+type ConnectionOption func(o *connectionOptions)
+
+// WithFailFast makes ConnectionManager.Get return ErrNotConnected instead of waiting for a reconnect
+func WithFailFast() ConnectionOption {
+	return func(o *connectionOptions) {
+		o.failFast = true
+	}
+}
+
+// ConnectionManager keeps a connection, e.g. a database or a message broker client without built-in reconnection,
+// and reconnects with policy when it breaks, it is safe for concurrent use
+type ConnectionManager[T any] struct {
+	policy  Policy
+	connect func(ctx context.Context) (T, error)
+	broken  func(err error) bool
+	opts    connectionOptions
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu        sync.Mutex
+	conn      T
+	connected bool
+	// generation is incremented for every established connection, so a failed caller
+	// resets only the connection it used, not one established after it
+	generation uint64
+	// connecting is closed when the running reconnect ends, nil if there is no running reconnect
+	connecting chan struct{}
+	// err is the error of the last failed reconnect
+	err error
+}
+
+// NewConnectionManager returns a manager connecting lazily with connect retried by policy,
+// broken reports whether an error returned by Do means the connection is broken and must be replaced.
+// Replaced connections implementing io.Closer are closed
+func NewConnectionManager[T any](
+	policy Policy,
+	connect func(ctx context.Context) (T, error),
+	broken func(err error) bool,
+	opts ...ConnectionOption,
+) *ConnectionManager[T] {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	m := &ConnectionManager[T]{
+		policy:  policy,
+		connect: connect,
+		broken:  broken,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	for _, opt := range opts {
+		opt(&m.opts)
+	}
+
+	return m
+}
+
+// Get returns the live connection, waits for a reconnect if there is none
+// unless WithFailFast is used, returns the reconnect error if it gave up
+func (m *ConnectionManager[T]) Get(ctx context.Context) (T, error) {
+	conn, _, err := m.get(ctx)
+
+	return conn, err
+}
+
+// get returns the live connection with its generation
+func (m *ConnectionManager[T]) get(ctx context.Context) (T, uint64, error) {
+	var zero T
+
+	m.mu.Lock()
+
+	if m.connected {
+		conn, generation := m.conn, m.generation
+		m.mu.Unlock()
+
+		return conn, generation, nil
+	}
+
+	connecting := m.reconnectLocked()
+	lastErr := m.err
+
+	m.mu.Unlock()
+
+	if m.opts.failFast {
+		if lastErr != nil {
+			return zero, 0, fmt.Errorf("%w: %w", ErrNotConnected, lastErr)
+		}
+
+		return zero, 0, ErrNotConnected
+	}
+
+	select {
+	case <-connecting:
+	case <-ctx.Done():
+		return zero, 0, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.connected {
+		return zero, 0, m.err
+	}
+
+	return m.conn, m.generation, nil
+}
+
+// Do calls f with the live connection, the connection is replaced if f returns a broken connection error,
+// unless it was already replaced by a concurrent caller
+func (m *ConnectionManager[T]) Do(ctx context.Context, f func(ctx context.Context, conn T) error) error {
+	conn, generation, err := m.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = f(ctx, conn)
+	if err != nil && m.broken != nil && m.broken(err) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		if m.generation == generation {
+			m.resetLocked()
+		}
+	}
+
+	return err
+}
+
+// Reset drops the current connection and starts a reconnect in background
+func (m *ConnectionManager[T]) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.resetLocked()
+}
+
+func (m *ConnectionManager[T]) resetLocked() {
+	if m.connected {
+		closeConnection(m.conn)
+
+		var zero T
+
+		m.conn, m.connected = zero, false
+	}
+
+	m.reconnectLocked()
+}
+
+// Close stops reconnects and closes the current connection
+func (m *ConnectionManager[T]) Close() {
+	m.cancel()
+
+	m.mu.Lock()
+	connecting := m.connecting
+	m.mu.Unlock()
+
+	if connecting != nil {
+		<-connecting
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.connected {
+		closeConnection(m.conn)
+
+		var zero T
+
+		m.conn, m.connected = zero, false
+	}
+}
+
+// reconnectLocked starts a reconnect if there is no running one and returns its done channel
+func (m *ConnectionManager[T]) reconnectLocked() <-chan struct{} {
+	if m.connecting != nil {
+		return m.connecting
+	}
+
+	connecting := make(chan struct{})
+	m.connecting = connecting
+
+	go m.reconnect(connecting)
+
+	return connecting
+}
+
+func (m *ConnectionManager[T]) reconnect(connecting chan struct{}) {
+	var conn T
+
+	err := m.policy.RepeatErr(m.ctx, func(ctx context.Context) (err error) {
+		conn, err = m.connect(ctx)
+
+		return err
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err == nil && m.ctx.Err() == nil {
+		m.conn, m.connected, m.err = conn, true, nil
+		m.generation++
+	} else {
+		if err == nil {
+			closeConnection(conn)
+
+			err = m.ctx.Err()
+		}
+
+		m.err = err
+	}
+
+	m.connecting = nil
+	close(connecting)
+}
+
+func closeConnection(conn any) {
+	if closer, ok := conn.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
@@ -0,0 +1,200 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+type testConn struct {
+	id     int64
+	closed atomic.Bool
+}
+
+func (c *testConn) Close() error {
+	c.closed.Store(true)
+
+	return nil
+}
+
+func Test_ConnectionManager(t *testing.T) {
+	t.Parallel()
+
+	var dials atomic.Int64
+
+	errBroken := errors.New("connection broken")
+
+	m := repeater.NewConnectionManager(
+		repeater.NewPolicy(repeater.WithMaxRetries(3)),
+		func(context.Context) (*testConn, error) {
+			id := dials.Add(1)
+			if id == 1 {
+				return nil, io.ErrUnexpectedEOF
+			}
+
+			return &testConn{id: id}, nil
+		},
+		func(err error) bool { return errors.Is(err, errBroken) },
+	)
+	defer m.Close()
+
+	first, err := m.Get(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if first.id != 2 {
+		t.Fatalf("wrong connection, expected the second dial, actual %d", first.id)
+	}
+
+	err = m.Do(context.Background(), func(_ context.Context, conn *testConn) error {
+		return errBroken
+	})
+	if !errors.Is(err, errBroken) {
+		t.Fatalf("expected broken error, actual %v", err)
+	}
+
+	second, err := m.Get(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if second.id != 3 || !first.closed.Load() {
+		t.Fatalf("broken connection is not replaced, actual %d, first closed %t", second.id, first.closed.Load())
+	}
+
+	m.Close()
+
+	if !second.closed.Load() {
+		t.Fatal("connection is not closed by Close")
+	}
+}
+
+func Test_ConnectionManager_WithFailFast(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	m := repeater.NewConnectionManager(
+		repeater.NewPolicy(),
+		func(ctx context.Context) (*testConn, error) {
+			select {
+			case <-release:
+				return &testConn{}, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+		nil,
+		repeater.WithFailFast(),
+	)
+	defer m.Close()
+
+	_, err := m.Get(context.Background())
+	if !errors.Is(err, repeater.ErrNotConnected) {
+		t.Fatalf("expected ErrNotConnected, actual %v", err)
+	}
+
+	close(release)
+
+	timeout := time.NewTimer(time.Second)
+	defer timeout.Stop()
+
+	for {
+		_, err = m.Get(context.Background())
+		if err == nil {
+			return
+		}
+
+		select {
+		case <-timeout.C:
+			t.Fatalf("connection is not established, last error %v", err)
+		default:
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func Test_ConnectionManager_GaveUp(t *testing.T) {
+	t.Parallel()
+
+	m := repeater.NewConnectionManager(
+		repeater.NewPolicy(repeater.WithMaxRetries(1)),
+		func(context.Context) (*testConn, error) { return nil, io.ErrUnexpectedEOF },
+		nil,
+	)
+	defer m.Close()
+
+	_, err := m.Get(context.Background())
+
+	repeatErr := &repeater.Error{}
+	if !errors.As(err, &repeatErr) || repeatErr.Attempts != 2 || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected reconnect error, actual %v", err)
+	}
+}
+
+func Test_ConnectionManager_ConcurrentBroken(t *testing.T) {
+	t.Parallel()
+
+	var dials atomic.Int64
+
+	errBroken := errors.New("connection broken")
+
+	m := repeater.NewConnectionManager(
+		repeater.NewPolicy(),
+		func(context.Context) (*testConn, error) {
+			return &testConn{id: dials.Add(1)}, nil
+		},
+		func(err error) bool { return errors.Is(err, errBroken) },
+	)
+	defer m.Close()
+
+	var (
+		entered  = make(chan struct{})
+		releases = [2]chan struct{}{make(chan struct{}), make(chan struct{})}
+		done     = [2]chan error{make(chan error), make(chan error)}
+	)
+
+	for i := range 2 {
+		go func() {
+			done[i] <- m.Do(context.Background(), func(_ context.Context, conn *testConn) error {
+				if conn.id != 1 {
+					t.Errorf("wrong connection, expected the first dial, actual %d", conn.id)
+				}
+
+				entered <- struct{}{}
+				<-releases[i]
+
+				return errBroken
+			})
+		}()
+	}
+
+	<-entered
+	<-entered
+
+	close(releases[0])
+	<-done[0]
+
+	second, err := m.Get(context.Background())
+	if err != nil || second.id != 2 {
+		t.Fatalf("expected the second dial, actual %v, error %v", second, err)
+	}
+
+	close(releases[1])
+	<-done[1]
+
+	current, err := m.Get(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if current != second || second.closed.Load() || dials.Load() != 2 {
+		t.Fatalf("connection reconnected after the failure must be kept, actual %d, closed %t, dials %d", current.id, second.closed.Load(), dials.Load())
+	}
+}
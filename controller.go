@@ -0,0 +1,111 @@
+package repeater
+
+import (
+	"context"
+	"sync"
+)
+
+// Controller lets an operator intervene in running repeats, e.g. long reconcile loops,
+// one Controller may be shared by many repeats and is safe for concurrent use, the zero Controller is ready to use
+type Controller struct {
+	mu     sync.Mutex
+	paused bool
+	// resumed is closed by Resume
+	resumed chan struct{}
+	// kicked is created by the first sleep waiting for it and closed by Kick
+	kicked chan struct{}
+}
+
+func NewController() *Controller {
+	return &Controller{}
+}
+
+// WithController makes repeats of the repeater obey c
+func WithController(c *Controller) Option {
+	return func(r *Repeater) {
+		r.controller = c
+	}
+}
+
+// Pause holds repeats before their next attempt until Resume, the current attempt and sleep are not interrupted
+func (c *Controller) Pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.paused {
+		return
+	}
+
+	c.paused = true
+	c.resumed = make(chan struct{})
+}
+
+// Resume releases repeats held by Pause
+func (c *Controller) Resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.paused {
+		return
+	}
+
+	c.paused = false
+	close(c.resumed)
+}
+
+func (c *Controller) Paused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.paused
+}
+
+// Kick interrupts sleeps in progress, so repeats retry immediately skipping the remaining backoff,
+// paused repeats stay paused
+func (c *Controller) Kick() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.kicked != nil {
+		close(c.kicked)
+		c.kicked = nil
+	}
+}
+
+// kickedChan returns the channel closed by the next Kick, nil for the nil Controller
+func (c *Controller) kickedChan() <-chan struct{} {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.kicked == nil {
+		c.kicked = make(chan struct{})
+	}
+
+	return c.kicked
+}
+
+// wait blocks while c is paused, returns false if ctx was done first
+func (c *Controller) wait(ctx context.Context) bool {
+	if c == nil {
+		return true
+	}
+
+	c.mu.Lock()
+	paused, resumed := c.paused, c.resumed
+	c.mu.Unlock()
+
+	if !paused {
+		return true
+	}
+
+	select {
+	case <-resumed:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
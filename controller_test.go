@@ -0,0 +1,107 @@
+package repeater_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Controller_PauseResume(t *testing.T) {
+	t.Parallel()
+
+	controller := repeater.NewController()
+	controller.Pause()
+
+	rp := repeater.New(repeater.ConstantProgression(0), repeater.WithController(controller))
+
+	var calls atomic.Int64
+
+	done := make(chan bool)
+
+	go func() {
+		done <- rp.RepeatContext(context.Background(), func(context.Context) bool {
+			return calls.Add(1) == 2
+		}, 5)
+	}()
+
+	time.Sleep(time.Millisecond * 20)
+
+	if calls.Load() != 0 {
+		t.Fatalf("paused repeat made %d calls", calls.Load())
+	}
+
+	controller.Resume()
+
+	if finished := <-done; !finished || calls.Load() != 2 {
+		t.Fatalf("unexpected result after resume, finished %t, calls %d", finished, calls.Load())
+	}
+
+	controller.Pause()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+
+	finished := rp.RepeatContext(ctx, func(context.Context) bool { return true }, 0)
+	if finished {
+		t.Fatal("paused repeat must give up when ctx is done")
+	}
+}
+
+func Test_Controller_Kick(t *testing.T) {
+	t.Parallel()
+
+	controllers := map[string]*repeater.Controller{
+		"new":  repeater.NewController(),
+		"zero": {},
+	}
+
+	for name, controller := range controllers {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// kick without sleeping repeats is a no-op
+			controller.Kick()
+
+			rp := repeater.New(repeater.ConstantProgression(time.Hour), repeater.WithController(controller))
+
+			var calls atomic.Int64
+
+			done := make(chan bool)
+
+			go func() {
+				done <- rp.RepeatContext(context.Background(), func(context.Context) bool {
+					return calls.Add(1) == 2
+				}, 1)
+			}()
+
+			for calls.Load() == 0 {
+				time.Sleep(time.Millisecond)
+			}
+
+			ticker := time.NewTicker(time.Millisecond)
+			defer ticker.Stop()
+
+			timeout := time.NewTimer(time.Second)
+			defer timeout.Stop()
+
+			for {
+				controller.Kick()
+
+				select {
+				case finished := <-done:
+					if !finished || calls.Load() != 2 {
+						t.Fatalf("unexpected result after kick, finished %t, calls %d", finished, calls.Load())
+					}
+
+					return
+				case <-ticker.C:
+				case <-timeout.C:
+					t.Fatal("kick didn't interrupt the sleep")
+				}
+			}
+		})
+	}
+}
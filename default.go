@@ -0,0 +1,33 @@
+package repeater
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+var defaultPolicy atomic.Pointer[Policy]
+
+func init() {
+	policy, _ := Lookup(StandardPolicyName)
+
+	defaultPolicy.Store(&policy)
+}
+
+// SetDefault replaces the policy used by RepeatDefault and RepeatDefaultContext,
+// the standard registry policy is used by default
+func SetDefault(policy Policy) {
+	defaultPolicy.Store(&policy)
+}
+
+// Default returns the policy set by SetDefault
+func Default() Policy {
+	return *defaultPolicy.Load()
+}
+
+func RepeatDefault(rf RepeatFunc) (finished bool) {
+	return Default().Repeat(rf)
+}
+
+func RepeatDefaultContext(ctx context.Context, rfctx RepeatFuncContext) (finished bool) {
+	return Default().RepeatContext(ctx, rfctx)
+}
@@ -0,0 +1,53 @@
+package repeater_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Default(t *testing.T) {
+	standard, _ := repeater.Lookup(repeater.StandardPolicyName)
+	if repeater.Default() != standard {
+		t.Fatal("standard policy must be the default one")
+	}
+
+	t.Cleanup(func() { repeater.SetDefault(standard) })
+
+	policy := repeater.NewPolicy(
+		repeater.WithProgression(repeater.ConstantProgression(time.Millisecond)),
+		repeater.WithMaxRetries(1),
+	)
+
+	repeater.SetDefault(policy)
+
+	calls := 0
+
+	finished := repeater.RepeatDefault(func() bool {
+		calls++
+
+		return false
+	})
+	if finished {
+		t.Fatal("expected not finished repeat")
+	}
+
+	finished = repeater.RepeatDefaultContext(context.Background(), func(context.Context) bool {
+		calls++
+
+		return calls == 3
+	})
+	if !finished {
+		t.Fatal("expected finished repeat")
+	}
+
+	if calls != 3 {
+		t.Fatalf("wrong calls count, expected 3, actual %d", calls)
+	}
+
+	if policy.Repeater().Stats().Calls != 2 {
+		t.Fatalf("wrong default policy calls, expected 2, actual %d", policy.Repeater().Stats().Calls)
+	}
+}
@@ -0,0 +1,71 @@
+package repeater
+
+import (
+	"context"
+	"time"
+)
+
+// Delay overrides the progression duration before the next retry, the zero Delay keeps it
+type Delay struct {
+	duration time.Duration
+	set      bool
+}
+
+// After returns Delay sleeping d before the next retry, zero d retries immediately
+func After(d time.Duration) Delay {
+	return Delay{duration: d, set: true}
+}
+
+// RepeatFuncDelay is a repeat func which may override the delay before the next retry,
+// e.g. by a server provided Retry-After, delay is ignored when finished
+type RepeatFuncDelay func(ctx context.Context) (finished bool, delay Delay)
+
+func RepeatDelay(ctx context.Context, progression DurationProgression, rf RepeatFuncDelay, retryCount uint64) (finished bool) {
+	rp := New(progression)
+
+	return rp.RepeatDelay(ctx, rf, retryCount)
+}
+
+// RepeatDelay repeats rf like RepeatContext, delays returned by rf replace progression durations,
+// max elapsed and expected latency checks use the overridden delay
+func (r *Repeater) RepeatDelay(ctx context.Context, rf RepeatFuncDelay, retryCount uint64) (finished bool) {
+	state := r.start()
+
+	return r.repeatContext(ctx, &state, func(ctx context.Context) bool {
+		finished, state.delay = rf(ctx)
+
+		return finished
+	}, retryCount)
+}
+
+// RepeatDelayErr repeats rf like RepeatDelay, returns nil if rf finished or *Error describing why the repeat gave up,
+// its Last is nil, so callers keeping the error of the last attempt themselves set it, e.g. HTTP clients
+func (r *Repeater) RepeatDelayErr(ctx context.Context, rf RepeatFuncDelay, retryCount uint64) error {
+	state := r.startClocked()
+
+	var attempts uint64
+
+	finished := r.repeatContext(ctx, &state, func(ctx context.Context) bool {
+		attempts++
+
+		var finished bool
+
+		finished, state.delay = rf(ctx)
+
+		return finished
+	}, retryCount)
+	if finished {
+		return nil
+	}
+
+	return &Error{
+		Attempts: attempts,
+		Elapsed:  state.elapsed(),
+		Exceeded: state.exhausted,
+		Aborted:  state.aborted,
+		Cause:    state.errCause(),
+		Context:  state.ctxErr,
+		InSleep:  state.inSleep,
+		Warnings: warningsFromContext(ctx),
+	}
+}
@@ -0,0 +1,73 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	"github.com/amidgo/repeater/repeatertest"
+)
+
+func Test_RepeatDelay(t *testing.T) {
+	t.Parallel()
+
+	recorder := &repeatertest.Recorder{}
+
+	rp := repeater.New(repeater.ConstantProgression(time.Millisecond), repeater.WithRecorder(recorder))
+
+	delays := []repeater.Delay{repeater.After(0), {}, repeater.After(time.Millisecond * 2)}
+	calls := 0
+
+	finished := rp.RepeatDelay(context.Background(), func(context.Context) (bool, repeater.Delay) {
+		calls++
+
+		if calls > len(delays) {
+			return true, repeater.After(time.Hour)
+		}
+
+		return false, delays[calls-1]
+	}, 5)
+	if !finished {
+		t.Fatal("expected finished repeat")
+	}
+
+	expectedDelays := []time.Duration{0, time.Millisecond, time.Millisecond * 2}
+
+	if !slices.Equal(expectedDelays, recorder.Delays()) {
+		t.Fatalf("wrong delays, expected %v, actual %v", expectedDelays, recorder.Delays())
+	}
+}
+
+func Test_RepeatDelayErr(t *testing.T) {
+	t.Parallel()
+
+	rp := repeater.New(repeater.ConstantProgression(time.Hour))
+
+	err := rp.RepeatDelayErr(context.Background(), func(context.Context) (bool, repeater.Delay) {
+		return false, repeater.After(0)
+	}, 2)
+
+	repeatErr := &repeater.Error{}
+	if !errors.As(err, &repeatErr) {
+		t.Fatalf("expected *repeater.Error, actual %v", err)
+	}
+
+	if repeatErr.Attempts != 3 || !repeatErr.Exceeded || repeatErr.Last != nil {
+		t.Fatalf("unexpected error %+v", repeatErr)
+	}
+
+	retryCountErr := &repeater.RetryCountError{}
+	if !errors.As(err, &retryCountErr) || retryCountErr.MaxAttempts != 3 {
+		t.Fatalf("expected *repeater.RetryCountError, actual %v", err)
+	}
+
+	err = rp.RepeatDelayErr(context.Background(), func(context.Context) (bool, repeater.Delay) {
+		return true, repeater.Delay{}
+	}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
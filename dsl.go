@@ -0,0 +1,182 @@
+package repeater
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultJitter is the jitter factor used by "+jitter" without an explicit factor
+const DefaultJitter = 0.1
+
+var dslBackoffKinds = map[string]BackoffKind{
+	"const":       ConstantBackoff,
+	"constant":    ConstantBackoff,
+	"arith":       ArifmeticBackoff,
+	"arifmetic":   ArifmeticBackoff,
+	"fib":         FibonacciBackoff,
+	"fibonacci":   FibonacciBackoff,
+	"exp":         ExponentialBackoff,
+	"exponential": ExponentialBackoff,
+}
+
+// ParseConfig parses Config from a single string, e.g.
+//
+//	exp(100ms,2,cap=10s)+jitter;max=5;elapsed=1m
+//
+// The first segment is a backoff: const(d), arith(initial,delta), fib(d) or exp(initial,factor),
+// each accepting an optional cap=d argument and followed by an optional +jitter or +jitter(factor).
+// The next segments are max=<retry count> and elapsed=<max elapsed duration>.
+func ParseConfig(s string) (Config, error) {
+	segments := strings.Split(strings.TrimSpace(s), ";")
+
+	cfg, err := parseBackoff(strings.TrimSpace(segments[0]))
+	if err != nil {
+		return Config{}, fmt.Errorf("%w: %q: %w", ErrInvalidConfig, s, err)
+	}
+
+	for _, segment := range segments[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(segment), "=")
+		if !ok {
+			return Config{}, fmt.Errorf("%w: %q: segment %q isn't key=value pair", ErrInvalidConfig, s, segment)
+		}
+
+		switch key {
+		case "max":
+			cfg.MaxRetries, err = strconv.ParseUint(value, 10, 64)
+		case "elapsed":
+			cfg.MaxElapsed, err = time.ParseDuration(value)
+		default:
+			err = fmt.Errorf("unknown key %q", key)
+		}
+
+		if err != nil {
+			return Config{}, fmt.Errorf("%w: %q: %w", ErrInvalidConfig, s, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// ParsePolicy builds Policy from ParseConfig, opts are passed to New
+func ParsePolicy(s string, opts ...Option) (Policy, error) {
+	cfg, err := ParseConfig(s)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	return cfg.Build(opts...)
+}
+
+func parseBackoff(s string) (cfg Config, err error) {
+	backoff, jitter, hasJitter := strings.Cut(s, "+")
+
+	name, args, err := parseCall(backoff)
+	if err != nil {
+		return Config{}, err
+	}
+
+	kind, ok := dslBackoffKinds[name]
+	if !ok {
+		return Config{}, fmt.Errorf("%w: %q", ErrUnknownBackoffKind, name)
+	}
+
+	cfg.Backoff = kind
+
+	positional := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		value, ok := strings.CutPrefix(arg, "cap=")
+		if !ok {
+			positional = append(positional, arg)
+
+			continue
+		}
+
+		cfg.Cap, err = time.ParseDuration(value)
+		if err != nil {
+			return Config{}, err
+		}
+	}
+
+	err = cfg.setBackoffArgs(positional)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if hasJitter {
+		cfg.Jitter, err = parseJitter(jitter)
+		if err != nil {
+			return Config{}, err
+		}
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) setBackoffArgs(args []string) (err error) {
+	keys := []string{"initial"}
+
+	switch c.Backoff {
+	case ArifmeticBackoff:
+		keys = append(keys, "delta")
+	case ExponentialBackoff:
+		keys = append(keys, "factor")
+	}
+
+	if len(args) != len(keys) {
+		return fmt.Errorf("%s backoff expects %d arguments, got %d", c.Backoff, len(keys), len(args))
+	}
+
+	for i, key := range keys {
+		err = c.set(key, args[i])
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+func parseJitter(s string) (float64, error) {
+	name, args, err := parseCall(s)
+	if err != nil {
+		return 0, err
+	}
+
+	if name != "jitter" {
+		return 0, fmt.Errorf("unknown modifier %q", name)
+	}
+
+	switch len(args) {
+	case 0:
+		return DefaultJitter, nil
+	case 1:
+		return strconv.ParseFloat(args[0], 64)
+	default:
+		return 0, fmt.Errorf("jitter expects at most 1 argument, got %d", len(args))
+	}
+}
+
+// parseCall parses "name" and "name(arg1,arg2)" expressions
+func parseCall(s string) (name string, args []string, err error) {
+	name, rest, ok := strings.Cut(s, "(")
+	if !ok {
+		return strings.TrimSpace(name), nil, nil
+	}
+
+	rest, ok = strings.CutSuffix(strings.TrimSpace(rest), ")")
+	if !ok {
+		return "", nil, fmt.Errorf("unclosed parenthesis in %q", s)
+	}
+
+	for _, arg := range strings.Split(rest, ",") {
+		arg = strings.TrimSpace(arg)
+		if arg != "" {
+			args = append(args, arg)
+		}
+	}
+
+	return strings.TrimSpace(name), args, nil
+}
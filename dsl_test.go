@@ -0,0 +1,119 @@
+package repeater_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	"github.com/amidgo/tester"
+)
+
+type ParseConfigTest struct {
+	Input          string
+	ExpectedConfig repeater.Config
+	ExpectedErr    error
+}
+
+func (p *ParseConfigTest) Name() string {
+	return fmt.Sprintf("parse %q", p.Input)
+}
+
+func (p *ParseConfigTest) Test(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := repeater.ParseConfig(p.Input)
+	if !errors.Is(err, p.ExpectedErr) {
+		t.Fatalf("wrong error, expected %v, actual %v", p.ExpectedErr, err)
+	}
+
+	if p.ExpectedConfig != cfg {
+		t.Fatalf("wrong config, expected %+v, actual %+v", p.ExpectedConfig, cfg)
+	}
+}
+
+func Test_ParseConfig(t *testing.T) {
+	t.Parallel()
+
+	tester.RunNamedTesters(t,
+		&ParseConfigTest{
+			Input: "exp(100ms,2,cap=10s)+jitter;max=5;elapsed=1m",
+			ExpectedConfig: repeater.Config{
+				Backoff:    repeater.ExponentialBackoff,
+				Initial:    time.Millisecond * 100,
+				Factor:     2,
+				Cap:        time.Second * 10,
+				Jitter:     repeater.DefaultJitter,
+				MaxRetries: 5,
+				MaxElapsed: time.Minute,
+			},
+		},
+		&ParseConfigTest{
+			Input: "const(1s);max=3",
+			ExpectedConfig: repeater.Config{
+				Backoff:    repeater.ConstantBackoff,
+				Initial:    time.Second,
+				MaxRetries: 3,
+			},
+		},
+		&ParseConfigTest{
+			Input: "arith(1s, 500ms)+jitter(0.5)",
+			ExpectedConfig: repeater.Config{
+				Backoff: repeater.ArifmeticBackoff,
+				Initial: time.Second,
+				Delta:   time.Millisecond * 500,
+				Jitter:  0.5,
+			},
+		},
+		&ParseConfigTest{
+			Input: "fibonacci(10ms); max=7",
+			ExpectedConfig: repeater.Config{
+				Backoff:    repeater.FibonacciBackoff,
+				Initial:    time.Millisecond * 10,
+				MaxRetries: 7,
+			},
+		},
+		&ParseConfigTest{
+			Input:       "linear(1s)",
+			ExpectedErr: repeater.ErrUnknownBackoffKind,
+		},
+		&ParseConfigTest{
+			Input:       "exp(100ms)",
+			ExpectedErr: repeater.ErrInvalidConfig,
+		},
+		&ParseConfigTest{
+			Input:       "const(1s",
+			ExpectedErr: repeater.ErrInvalidConfig,
+		},
+		&ParseConfigTest{
+			Input:       "const(1s)+noise",
+			ExpectedErr: repeater.ErrInvalidConfig,
+		},
+		&ParseConfigTest{
+			Input:       "const(1s);retries=5",
+			ExpectedErr: repeater.ErrInvalidConfig,
+		},
+	)
+}
+
+func Test_ParsePolicy(t *testing.T) {
+	t.Parallel()
+
+	policy, err := repeater.ParsePolicy("const(1ms);max=2")
+	if err != nil {
+		t.Fatalf("parse policy: %s", err)
+	}
+
+	calls := 0
+
+	policy.Repeat(func() bool {
+		calls++
+
+		return false
+	})
+
+	if calls != 3 {
+		t.Fatalf("wrong calls count, expected 3, actual %d", calls)
+	}
+}
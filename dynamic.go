@@ -0,0 +1,55 @@
+package repeater
+
+import (
+	"context"
+	"sync"
+)
+
+// PolicyProvider supplies the policy at the start of every repeat, so long-lived workers
+// pick up tuning changes, e.g. from a live config store, without being restarted
+type PolicyProvider func() Policy
+
+func (p PolicyProvider) Repeat(rf RepeatFunc) (finished bool) {
+	return p().Repeat(rf)
+}
+
+func (p PolicyProvider) RepeatContext(ctx context.Context, rfctx RepeatFuncContext) (finished bool) {
+	return p().RepeatContext(ctx, rfctx)
+}
+
+func (p PolicyProvider) RepeatErr(ctx context.Context, rf RepeatErrFunc) error {
+	return p().RepeatErr(ctx, rf)
+}
+
+// FromConfig returns PolicyProvider building the policy from the config returned by config,
+// the policy is rebuilt only when the config changes, so repeater stats survive between repeats.
+// fallback is used until config returns a valid config and while it returns errors
+// the last valid policy is kept, opts are passed to Config.Build
+func FromConfig(config func() (Config, error), fallback Policy, opts ...Option) PolicyProvider {
+	var (
+		mu      sync.Mutex
+		current = fallback
+		built   Config
+		ok      bool
+	)
+
+	return func() Policy {
+		cfg, err := config()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err != nil || ok && cfg == built {
+			return current
+		}
+
+		policy, err := cfg.Build(opts...)
+		if err != nil {
+			return current
+		}
+
+		current, built, ok = policy, cfg, true
+
+		return current
+	}
+}
@@ -0,0 +1,57 @@
+package repeater_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_FromConfig(t *testing.T) {
+	t.Parallel()
+
+	fallback := repeater.NewPolicy(repeater.WithMaxRetries(1))
+
+	var (
+		cfg       repeater.Config
+		configErr error
+	)
+
+	provider := repeater.FromConfig(func() (repeater.Config, error) { return cfg, configErr }, fallback)
+
+	if provider().RetryCount() != 1 {
+		t.Fatalf("expected fallback policy for invalid config, actual retry count %d", provider().RetryCount())
+	}
+
+	cfg = repeater.Config{Backoff: repeater.ConstantBackoff, MaxRetries: 3}
+
+	first := provider()
+	if first.RetryCount() != 3 {
+		t.Fatalf("wrong retry count, expected 3, actual %d", first.RetryCount())
+	}
+
+	if provider().Repeater() != first.Repeater() {
+		t.Fatal("policy is rebuilt for the same config")
+	}
+
+	configErr = errors.New("config store unavailable")
+
+	if provider().RetryCount() != 3 {
+		t.Fatalf("expected the last valid policy on config error, actual retry count %d", provider().RetryCount())
+	}
+
+	cfg, configErr = repeater.Config{Backoff: repeater.ConstantBackoff, Initial: time.Nanosecond, MaxRetries: 2}, nil
+
+	calls := 0
+
+	provider.Repeat(func() bool {
+		calls++
+
+		return false
+	})
+
+	if calls != 3 {
+		t.Fatalf("wrong calls, expected 3, actual %d", calls)
+	}
+}
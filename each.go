@@ -0,0 +1,119 @@
+package repeater
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Outcome is the result of an item repeated by Each
+type Outcome uint8
+
+const (
+	// OutcomeSucceeded means the item op returned nil
+	OutcomeSucceeded Outcome = iota + 1
+	// OutcomeAborted means the item op returned an Abort error or the repeat was stopped by an abort option
+	OutcomeAborted
+	// OutcomeExhausted means the retries of the item were exhausted
+	OutcomeExhausted
+	// OutcomeCanceled means the context was done before the item succeeded
+	OutcomeCanceled
+	// OutcomeStopped means the item op returned a Stop error
+	OutcomeStopped
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeSucceeded:
+		return "succeeded"
+	case OutcomeAborted:
+		return "aborted"
+	case OutcomeExhausted:
+		return "exhausted"
+	case OutcomeCanceled:
+		return "canceled"
+	case OutcomeStopped:
+		return "stopped"
+	default:
+		return "unknown"
+	}
+}
+
+// ItemResult is the outcome of an item repeated by Each, Err is *Error unless the item succeeded or stopped
+type ItemResult[T any] struct {
+	Item    T
+	Outcome Outcome
+	Err     error
+}
+
+type eachOptions struct {
+	concurrency int
+}
+
+type EachOption func(o *eachOptions)
+
+// WithConcurrency sets the max number of items repeated at once, one by default
+func WithConcurrency(concurrency int) EachOption {
+	return func(o *eachOptions) {
+		o.concurrency = concurrency
+	}
+}
+
+// Each repeats op for every item with policy independently, so a flaky item is retried
+// without restarting the whole batch. Results are returned in the order of items
+func Each[T any](ctx context.Context, policy Policy, items []T, op func(ctx context.Context, item T) error, opts ...EachOption) []ItemResult[T] {
+	o := eachOptions{concurrency: 1}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	results := make([]ItemResult[T], len(items))
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, max(o.concurrency, 1))
+	)
+
+	for i, item := range items {
+		sem <- struct{}{}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := policy.RepeatErr(ctx, func(ctx context.Context) error {
+				return op(ctx, item)
+			})
+
+			results[i] = ItemResult[T]{
+				Item:    item,
+				Outcome: itemOutcome(ctx, err),
+				Err:     err,
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+func itemOutcome(ctx context.Context, err error) Outcome {
+	var repeatErr *Error
+
+	switch {
+	case err == nil:
+		return OutcomeSucceeded
+	case errors.Is(err, ErrStopped):
+		return OutcomeStopped
+	case errors.As(err, &repeatErr) && repeatErr.Exceeded:
+		return OutcomeExhausted
+	case ctx.Err() != nil:
+		return OutcomeCanceled
+	default:
+		return OutcomeAborted
+	}
+}
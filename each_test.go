@@ -0,0 +1,111 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Each(t *testing.T) {
+	t.Parallel()
+
+	errTemporary := errors.New("temporary")
+
+	var (
+		calls   [4]atomic.Int64
+		running atomic.Int64
+		peak    atomic.Int64
+	)
+
+	op := func(_ context.Context, item int) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+
+		call := calls[item].Add(1)
+
+		switch item {
+		case 1:
+			if call == 1 {
+				return errTemporary
+			}
+		case 2:
+			return repeater.Abort(errTemporary)
+		case 3:
+			return errTemporary
+		}
+
+		return nil
+	}
+
+	results := repeater.Each(
+		context.Background(),
+		repeater.NewPolicy(repeater.WithMaxRetries(2)),
+		[]int{0, 1, 2, 3},
+		op,
+		repeater.WithConcurrency(2),
+	)
+
+	expected := []repeater.Outcome{
+		repeater.OutcomeSucceeded,
+		repeater.OutcomeSucceeded,
+		repeater.OutcomeAborted,
+		repeater.OutcomeExhausted,
+	}
+
+	for i, result := range results {
+		if result.Item != i || result.Outcome != expected[i] {
+			t.Fatalf("wrong result %d, expected %s, actual %+v", i, expected[i], result)
+		}
+	}
+
+	if results[3].Err == nil || !errors.Is(results[3].Err, errTemporary) {
+		t.Fatalf("unexpected error %v", results[3].Err)
+	}
+
+	if calls[1].Load() != 2 || calls[3].Load() != 3 {
+		t.Fatalf("unexpected calls %d and %d", calls[1].Load(), calls[3].Load())
+	}
+
+	if peak.Load() > 2 {
+		t.Fatalf("concurrency exceeded, peak %d", peak.Load())
+	}
+}
+
+func Test_Each_Canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := repeater.Each(ctx, repeater.NewPolicy(), []string{"a"}, func(context.Context, string) error {
+		t.Error("op must not be called")
+
+		return nil
+	})
+
+	if results[0].Outcome != repeater.OutcomeCanceled || results[0].Outcome.String() != "canceled" {
+		t.Fatalf("unexpected result %+v", results[0])
+	}
+}
+
+func Test_Each_Stopped(t *testing.T) {
+	t.Parallel()
+
+	results := repeater.Each(context.Background(), repeater.NewPolicy(repeater.WithMaxRetries(3)), []string{"a"}, func(context.Context, string) error {
+		return repeater.Stop()
+	})
+
+	if results[0].Outcome != repeater.OutcomeStopped || !errors.Is(results[0].Err, repeater.ErrStopped) {
+		t.Fatalf("unexpected result %+v", results[0])
+	}
+}
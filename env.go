@@ -0,0 +1,66 @@
+package repeater
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+var envConfigKeys = []struct {
+	env string
+	key string
+}{
+	{"BACKOFF", "backoff"},
+	{"INITIAL", "initial"},
+	{"DELTA", "delta"},
+	{"FACTOR", "factor"},
+	{"CAP", "cap"},
+	{"JITTER", "jitter"},
+	{"COUNT", "max_retries"},
+	{"MAX_ELAPSED", "max_elapsed"},
+}
+
+// ConfigFromEnv reads Config from RETRY_<PREFIX>_BACKOFF, _INITIAL, _DELTA, _FACTOR,
+// _CAP, _JITTER, _COUNT and _MAX_ELAPSED environment variables, prefix is uppercased.
+// Unset variables keep zero values, values use the Config.UnmarshalText format.
+func ConfigFromEnv(prefix string) (Config, error) {
+	var cfg Config
+
+	for _, k := range envConfigKeys {
+		name := envName(prefix, k.env)
+
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		err := cfg.set(k.key, value)
+		if err != nil {
+			return Config{}, fmt.Errorf("%w: %s: %w", ErrInvalidConfig, name, err)
+		}
+	}
+
+	if cfg.Backoff == "" {
+		return Config{}, fmt.Errorf("%w: %s is not set", ErrInvalidConfig, envName(prefix, "BACKOFF"))
+	}
+
+	return cfg, nil
+}
+
+// FromEnv builds Policy from ConfigFromEnv, opts are passed to New
+func FromEnv(prefix string, opts ...Option) (Policy, error) {
+	cfg, err := ConfigFromEnv(prefix)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	return cfg.Build(opts...)
+}
+
+func envName(prefix, key string) string {
+	if prefix == "" {
+		return "RETRY_" + key
+	}
+
+	return "RETRY_" + strings.ToUpper(prefix) + "_" + key
+}
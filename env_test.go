@@ -0,0 +1,60 @@
+package repeater_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_ConfigFromEnv(t *testing.T) {
+	t.Setenv("RETRY_PAYMENTS_BACKOFF", "exponential")
+	t.Setenv("RETRY_PAYMENTS_INITIAL", "100ms")
+	t.Setenv("RETRY_PAYMENTS_FACTOR", "2")
+	t.Setenv("RETRY_PAYMENTS_CAP", "5s")
+	t.Setenv("RETRY_PAYMENTS_COUNT", "4")
+	t.Setenv("RETRY_PAYMENTS_MAX_ELAPSED", "30s")
+
+	cfg, err := repeater.ConfigFromEnv("payments")
+	if err != nil {
+		t.Fatalf("read config from env: %s", err)
+	}
+
+	expected := repeater.Config{
+		Backoff:    repeater.ExponentialBackoff,
+		Initial:    time.Millisecond * 100,
+		Factor:     2,
+		Cap:        time.Second * 5,
+		MaxRetries: 4,
+		MaxElapsed: time.Second * 30,
+	}
+
+	if expected != cfg {
+		t.Fatalf("wrong config, expected %+v, actual %+v", expected, cfg)
+	}
+
+	policy, err := repeater.FromEnv("payments")
+	if err != nil {
+		t.Fatalf("build policy from env: %s", err)
+	}
+
+	if policy.RetryCount() != 4 {
+		t.Fatalf("wrong retry count, expected 4, actual %d", policy.RetryCount())
+	}
+}
+
+func Test_ConfigFromEnv_Invalid(t *testing.T) {
+	t.Setenv("RETRY_ORDERS_BACKOFF", "constant")
+	t.Setenv("RETRY_ORDERS_COUNT", "-1")
+
+	_, err := repeater.ConfigFromEnv("orders")
+	if !errors.Is(err, repeater.ErrInvalidConfig) {
+		t.Fatalf("wrong error, expected %s, actual %v", repeater.ErrInvalidConfig, err)
+	}
+
+	_, err = repeater.FromEnv("missing")
+	if !errors.Is(err, repeater.ErrInvalidConfig) {
+		t.Fatalf("wrong error for missing backoff, expected %s, actual %v", repeater.ErrInvalidConfig, err)
+	}
+}
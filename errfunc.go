@@ -0,0 +1,162 @@
+package repeater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// RepeatErrFunc is a repeat func reporting failures with an error,
+// non-nil errors are retried unless they wrap an Abort error, nil finishes the repeat
+type RepeatErrFunc func(ctx context.Context) error
+
+type abortError struct {
+	err error
+}
+
+func (a *abortError) Error() string {
+	return a.err.Error()
+}
+
+func (a *abortError) Unwrap() error {
+	return a.err
+}
+
+// Abort marks err as not retryable, RepeatErr stops without retries when it gets an error wrapping it,
+// so the abort error may be annotated later with fmt.Errorf and %w. Abort returns nil if err is nil
+func Abort(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &abortError{err: err}
+}
+
+// Abortf is Abort(fmt.Errorf(format, args...))
+func Abortf(format string, args ...any) error {
+	return Abort(fmt.Errorf(format, args...))
+}
+
+// ErrStopped is returned by Stop, a repeat func returning an error wrapping it stops the repeat
+// without retries, the repeat neither succeeded nor failed, e.g. the work became irrelevant
+var ErrStopped = errors.New("repeat stopped")
+
+// Stop returns ErrStopped, RepeatErr returns the stop error as is instead of *Error,
+// so callers distinguish stopped repeats with errors.Is(err, ErrStopped). It may be annotated with fmt.Errorf and %w:
+//
+//	if errors.Is(err, ErrEntityNotFound) {
+//		return fmt.Errorf("entity %d deleted: %w", id, repeater.Stop())
+//	}
+func Stop() error {
+	return ErrStopped
+}
+
+// IsAbort reports whether err wraps an Abort error
+func IsAbort(err error) bool {
+	var abortErr *abortError
+
+	return errors.As(err, &abortErr)
+}
+
+func RepeatErr(ctx context.Context, progression DurationProgression, rf RepeatErrFunc, retryCount uint64) error {
+	rp := New(progression)
+
+	return rp.RepeatErr(ctx, rf, retryCount)
+}
+
+// RepeatErr repeats rf like RepeatContext, returns nil if rf succeeded
+// or *Error with the last rf error if the repeat gave up or was aborted, see Stop for stopped repeats.
+// Errors of earlier attempts are dropped, so the returned error doesn't grow with retries,
+// use WithErrorHistory to keep a bounded number of them
+func (r *Repeater) RepeatErr(ctx context.Context, rf RepeatErrFunc, retryCount uint64) error {
+	state := r.startClocked()
+
+	var (
+		attempts uint64
+		lastErr  error
+		// sameErrs is the number of consecutive attempts returned the same error as the last one
+		sameErrs uint64
+		history  = errorHistory{keep: r.historyKeep}
+	)
+
+	finished := r.repeatContext(ctx, &state, func(ctx context.Context) bool {
+		attempts++
+
+		prevErr := lastErr
+
+		lastErr = rf(ctx)
+		state.err = lastErr
+
+		if IsAbort(lastErr) || r.abortOnErr(lastErr) {
+			state.aborted = true
+		}
+
+		if errors.Is(lastErr, ErrStopped) {
+			state.aborted, state.stopped = true, true
+		}
+
+		if r.historyKeep > 0 && lastErr != nil {
+			history.add(lastErr)
+		}
+
+		if r.giveUpAfterSame > 0 && lastErr != nil {
+			sameErrs = r.countSame(prevErr, lastErr, sameErrs)
+			if sameErrs >= r.giveUpAfterSame {
+				state.aborted = true
+				state.cause = ErrSameError
+			}
+		}
+
+		return lastErr == nil
+	}, retryCount)
+	if finished {
+		return nil
+	}
+
+	if state.stopped {
+		return lastErr
+	}
+
+	repeatErr := &Error{
+		Attempts: attempts,
+		Elapsed:  state.elapsed(),
+		Last:     lastErr,
+		Exceeded: state.exhausted,
+		Aborted:  state.aborted,
+		Cause:    state.errCause(),
+		Context:  state.ctxErr,
+		InSleep:  state.inSleep,
+		Warnings: warningsFromContext(ctx),
+	}
+
+	if r.historyKeep > 0 {
+		repeatErr.History = history.errors()
+		repeatErr.Omitted = history.omitted
+	}
+
+	return repeatErr
+}
+
+// abortOnErr reports whether err matches any error of WithAbortOn
+func (r *Repeater) abortOnErr(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	for _, abortErr := range r.abortOn {
+		if errors.Is(err, abortErr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// countSame returns the number of consecutive same errors ending with err
+func (r *Repeater) countSame(prevErr, err error, sameErrs uint64) uint64 {
+	if prevErr != nil && r.sameErr(prevErr, err) {
+		return sameErrs + 1
+	}
+
+	return 1
+}
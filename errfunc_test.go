@@ -0,0 +1,266 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_RepeatErr(t *testing.T) {
+	t.Parallel()
+
+	t.Run("finished", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+
+		err := repeater.RepeatErr(context.Background(), repeater.ConstantProgression(0), func(context.Context) error {
+			calls++
+
+			if calls < 3 {
+				return io.ErrUnexpectedEOF
+			}
+
+			return nil
+		}, 3)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if calls != 3 {
+			t.Fatalf("expected 3 calls, actual %d", calls)
+		}
+	})
+
+	t.Run("exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		err := repeater.RepeatErr(context.Background(), repeater.ConstantProgression(0), func(context.Context) error {
+			return io.ErrUnexpectedEOF
+		}, 2)
+
+		repeatErr := &repeater.Error{}
+		if !errors.As(err, &repeatErr) {
+			t.Fatalf("expected *repeater.Error, actual %v", err)
+		}
+
+		if repeatErr.Attempts != 3 || !repeatErr.Exceeded || repeatErr.Elapsed <= 0 {
+			t.Fatalf("unexpected error %+v", repeatErr)
+		}
+
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("expected error wraps last attempt error, actual %v", err)
+		}
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		err := repeater.RepeatErr(ctx, repeater.ConstantProgression(0), func(context.Context) error {
+			cancel()
+
+			return io.ErrUnexpectedEOF
+		}, 2)
+
+		repeatErr := &repeater.Error{}
+		if !errors.As(err, &repeatErr) {
+			t.Fatalf("expected *repeater.Error, actual %v", err)
+		}
+
+		if repeatErr.Attempts != 1 || repeatErr.Exceeded {
+			t.Fatalf("unexpected error %+v", repeatErr)
+		}
+	})
+	t.Run("aborted", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+
+		err := repeater.RepeatErr(context.Background(), repeater.ConstantProgression(0), func(context.Context) error {
+			calls++
+
+			return fmt.Errorf("request %d: %w", calls, repeater.Abortf("bad request: %w", io.ErrUnexpectedEOF))
+		}, 2)
+
+		if calls != 1 {
+			t.Fatalf("expected 1 call, actual %d", calls)
+		}
+
+		repeatErr := &repeater.Error{}
+		if !errors.As(err, &repeatErr) {
+			t.Fatalf("expected *repeater.Error, actual %v", err)
+		}
+
+		if repeatErr.Exceeded || !repeater.IsAbort(err) || !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("unexpected error %+v", repeatErr)
+		}
+
+		expected := "request 1: bad request: unexpected EOF"
+		if repeatErr.Last.Error() != expected {
+			t.Fatalf("wrong last error message, expected %q, actual %q", expected, repeatErr.Last.Error())
+		}
+	})
+}
+
+func Test_RepeatErr_WithMaxTotalBackoff(t *testing.T) {
+	t.Parallel()
+
+	rp := repeater.New(
+		repeater.ConstantProgression(time.Millisecond*10),
+		repeater.WithMaxTotalBackoff(time.Millisecond*25),
+	)
+
+	calls := 0
+
+	err := rp.RepeatErr(context.Background(), func(context.Context) error {
+		calls++
+
+		// attempts are slow, but only sleeps count against the limit
+		time.Sleep(time.Millisecond * 20)
+
+		return io.ErrUnexpectedEOF
+	}, 10)
+
+	if calls != 3 {
+		t.Fatalf("wrong calls, expected 3, actual %d", calls)
+	}
+
+	if !errors.Is(err, repeater.ErrMaxTotalBackoff) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected error wraps ErrMaxTotalBackoff and last attempt error, actual %v", err)
+	}
+
+	repeatErr := &repeater.Error{}
+	if !errors.As(err, &repeatErr) || !repeatErr.Exceeded {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func Test_Abort_Nil(t *testing.T) {
+	t.Parallel()
+
+	if err := repeater.Abort(nil); err != nil {
+		t.Fatalf("wrong abort of nil error, expected nil, actual %v", err)
+	}
+
+	rp := repeater.New(repeater.ConstantProgression(0))
+
+	err := rp.RepeatErr(context.Background(), func(context.Context) error {
+		return repeater.Abort(nil)
+	}, 5)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func Test_RepeatErr_WithAbortOn(t *testing.T) {
+	t.Parallel()
+
+	errForbidden := errors.New("forbidden")
+
+	rp := repeater.New(repeater.ConstantProgression(0), repeater.WithAbortOn(errForbidden))
+
+	calls := 0
+
+	err := rp.RepeatErr(context.Background(), func(context.Context) error {
+		calls++
+
+		if calls == 1 {
+			return io.ErrUnexpectedEOF
+		}
+
+		return fmt.Errorf("get user: %w", errForbidden)
+	}, 5)
+
+	if calls != 2 {
+		t.Fatalf("wrong calls, expected 2, actual %d", calls)
+	}
+
+	repeatErr := &repeater.Error{}
+	if !errors.As(err, &repeatErr) || repeatErr.Exceeded {
+		t.Fatalf("expected not exceeded *repeater.Error, actual %v", err)
+	}
+
+	if !errors.Is(err, errForbidden) {
+		t.Fatalf("expected error wraps abort error, actual %v", err)
+	}
+}
+
+func Test_RepeatErr_WithGiveUpAfterSame(t *testing.T) {
+	t.Parallel()
+
+	errTimeout := errors.New("timeout")
+
+	results := []error{io.ErrUnexpectedEOF, errTimeout, io.ErrUnexpectedEOF, io.ErrUnexpectedEOF, io.ErrUnexpectedEOF, nil}
+
+	rp := repeater.New(repeater.ConstantProgression(0), repeater.WithGiveUpAfterSame(3))
+
+	calls := 0
+
+	err := rp.RepeatErr(context.Background(), func(context.Context) error {
+		calls++
+
+		return results[calls-1]
+	}, 10)
+
+	if calls != 5 {
+		t.Fatalf("wrong calls, expected 5, actual %d", calls)
+	}
+
+	if !errors.Is(err, repeater.ErrSameError) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected error wraps ErrSameError and last attempt error, actual %v", err)
+	}
+
+	rp = repeater.New(
+		repeater.ConstantProgression(0),
+		repeater.WithGiveUpAfterSame(2, func(prev, err error) bool { return prev.Error() == err.Error() }),
+	)
+
+	calls = 0
+
+	err = rp.RepeatErr(context.Background(), func(context.Context) error {
+		calls++
+
+		return fmt.Errorf("attempt failed")
+	}, 10)
+
+	if calls != 2 || !errors.Is(err, repeater.ErrSameError) {
+		t.Fatalf("wrong calls or error, expected 2 calls and ErrSameError, actual %d calls and %v", calls, err)
+	}
+}
+
+func Test_RepeatErr_Stop(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+
+	err := repeater.RepeatErr(context.Background(), repeater.ConstantProgression(0), func(context.Context) error {
+		calls++
+
+		if calls == 1 {
+			return io.ErrUnexpectedEOF
+		}
+
+		return fmt.Errorf("user deleted: %w", repeater.Stop())
+	}, 5)
+
+	if calls != 2 {
+		t.Fatalf("wrong calls, expected 2, actual %d", calls)
+	}
+
+	if !errors.Is(err, repeater.ErrStopped) || err.Error() != "user deleted: repeat stopped" {
+		t.Fatalf("expected stop error, actual %v", err)
+	}
+
+	repeatErr := &repeater.Error{}
+	if errors.As(err, &repeatErr) {
+		t.Fatalf("stopped repeat must not return *repeater.Error, actual %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package repeater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrRetryCountExceeded is matched by RetryCountError, the Error cause when the retry count was exhausted
+	ErrRetryCountExceeded = errors.New("retries exceeded")
+	// ErrMaxTotalBackoff is the Error cause when the sum of sleeps would exceed WithMaxTotalBackoff
+	ErrMaxTotalBackoff = errors.New("max total backoff exceeded")
+	// ErrSameError is the Error cause when the same error was returned WithGiveUpAfterSame times in a row
+	ErrSameError = errors.New("same error repeated")
+	// ErrBudgetExhausted is the Error cause when the retry budget of ContextWithBudget was exhausted
+	ErrBudgetExhausted = errors.New("retry budget exhausted")
+)
+
+// RetryCountError is the Error cause when the retry count was exhausted, it matches ErrRetryCountExceeded,
+// so callers report "failed after 5/5 attempts" without counting them
+type RetryCountError struct {
+	// number of made attempts, including the initial one
+	Attempts uint64
+	// retry count of the repeat plus the initial attempt
+	MaxAttempts uint64
+}
+
+func (e *RetryCountError) Error() string {
+	return ErrRetryCountExceeded.Error()
+}
+
+func (e *RetryCountError) Is(target error) bool {
+	return target == ErrRetryCountExceeded
+}
+
+// Error describes a repeat which gave up, it unwraps to the error of the last attempt
+type Error struct {
+	// number of made attempts, including the initial one
+	Attempts uint64
+	// time since the repeat start
+	Elapsed time.Duration
+	// error of the last attempt, may be nil
+	Last error
+	// Exceeded is set when retries or another limit were exhausted, false means the repeat
+	// was either aborted or stopped by context, see Aborted and Context
+	Exceeded bool
+	// Aborted is set when the repeat func aborted the repeat, e.g. with an Abort error or WithAbortOn
+	Aborted bool
+	// Cause is the error of the limit which stopped the repeat, e.g. ErrMaxTotalBackoff or *RetryCountError, may be nil
+	Cause error
+	// History holds errors of failed attempts in order, only with WithErrorHistory
+	History []error
+	// Omitted is the number of attempt errors dropped from the middle of History
+	Omitted uint64
+	// Warnings holds warnings collected by the repeat context so far, only with ContextWithWarnings
+	Warnings []error
+	// Context is context.Canceled or context.DeadlineExceeded if the context stopped the repeat,
+	// DeadlineExceeded is also set when the deadline would pass during the next sleep
+	Context error
+	// InSleep is set when the context stopped the repeat during the sleep before a retry,
+	// otherwise it was done during an attempt or before the first one
+	InSleep bool
+}
+
+func (e *Error) Error() string {
+	reason := "stopped by context"
+	if e.Context != nil {
+		reason = e.contextReason()
+	}
+
+	if e.Aborted {
+		reason = "aborted"
+	}
+
+	if e.Exceeded {
+		reason = "retries exceeded"
+	}
+
+	if e.Cause != nil {
+		reason = e.Cause.Error()
+	}
+
+	if e.Last == nil {
+		return fmt.Sprintf("repeat gave up after %d attempts in %s, %s", e.Attempts, e.Elapsed, reason)
+	}
+
+	return fmt.Sprintf("repeat gave up after %d attempts in %s, %s: %s", e.Attempts, e.Elapsed, reason, e.Last)
+}
+
+// contextReason describes how the context stopped the repeat
+func (e *Error) contextReason() string {
+	if e.InSleep {
+		return e.Context.Error() + " during sleep"
+	}
+
+	return e.Context.Error() + " during attempt"
+}
+
+// Canceled reports whether the repeat was stopped by the context cancellation
+func (e *Error) Canceled() bool {
+	return errors.Is(e.Context, context.Canceled)
+}
+
+// DeadlineExceeded reports whether the repeat was stopped by the context deadline
+func (e *Error) DeadlineExceeded() bool {
+	return errors.Is(e.Context, context.DeadlineExceeded)
+}
+
+func (e *Error) Unwrap() []error {
+	errs := make([]error, 0, 3)
+
+	if e.Last != nil {
+		errs = append(errs, e.Last)
+	}
+
+	if e.Cause != nil {
+		errs = append(errs, e.Cause)
+	}
+
+	if e.Context != nil {
+		errs = append(errs, e.Context)
+	}
+
+	return errs
+}
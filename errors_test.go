@@ -0,0 +1,132 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Error(t *testing.T) {
+	t.Parallel()
+
+	err := error(&repeater.Error{
+		Attempts: 3,
+		Elapsed:  time.Second,
+		Last:     io.ErrUnexpectedEOF,
+		Exceeded: true,
+	})
+
+	expected := "repeat gave up after 3 attempts in 1s, retries exceeded: unexpected EOF"
+	if err.Error() != expected {
+		t.Fatalf("wrong error message, expected %q, actual %q", expected, err.Error())
+	}
+
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatal("expected error wraps last attempt error")
+	}
+
+	err = &repeater.Error{Attempts: 1, Elapsed: time.Millisecond}
+
+	expected = "repeat gave up after 1 attempts in 1ms, stopped by context"
+	if err.Error() != expected {
+		t.Fatalf("wrong error message, expected %q, actual %q", expected, err.Error())
+	}
+
+	if errors.Unwrap(err) != nil {
+		t.Fatal("expected no wrapped error")
+	}
+}
+
+func Test_RetryCountError(t *testing.T) {
+	t.Parallel()
+
+	err := repeater.RepeatErr(context.Background(), repeater.ConstantProgression(0), func(context.Context) error {
+		return io.ErrUnexpectedEOF
+	}, 4)
+
+	if !errors.Is(err, repeater.ErrRetryCountExceeded) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected retry count exceeded error, actual %v", err)
+	}
+
+	var countErr *repeater.RetryCountError
+	if !errors.As(err, &countErr) || countErr.Attempts != 5 || countErr.MaxAttempts != 5 {
+		t.Fatalf("expected 5/5 attempts, actual %+v", countErr)
+	}
+
+	err = repeater.RepeatErr(context.Background(), repeater.ConstantProgression(0), func(context.Context) error {
+		return repeater.Abort(io.ErrUnexpectedEOF)
+	}, 4)
+
+	if errors.Is(err, repeater.ErrRetryCountExceeded) {
+		t.Fatalf("aborted repeat must not exceed retry count, actual %v", err)
+	}
+}
+
+func Test_Error_Context(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := repeater.RepeatErr(ctx, repeater.ConstantProgression(time.Hour), func(context.Context) error {
+		time.AfterFunc(time.Millisecond, cancel)
+
+		return io.ErrUnexpectedEOF
+	}, 2)
+
+	repeatErr := &repeater.Error{}
+	if !errors.As(err, &repeatErr) || !repeatErr.Canceled() || repeatErr.DeadlineExceeded() || !repeatErr.InSleep {
+		t.Fatalf("expected cancellation during sleep, actual %v", err)
+	}
+
+	expected := "repeat gave up after 1 attempts in " + repeatErr.Elapsed.String() + ", context canceled during sleep: unexpected EOF"
+	if err.Error() != expected {
+		t.Fatalf("wrong error message, expected %q, actual %q", expected, err.Error())
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	rp := repeater.New(repeater.ConstantProgression(time.Hour*2), repeater.WithExpectedLatency(time.Millisecond))
+
+	err = rp.RepeatErr(ctx, func(context.Context) error {
+		return io.ErrUnexpectedEOF
+	}, 2)
+
+	if !errors.As(err, &repeatErr) || !repeatErr.DeadlineExceeded() || !repeatErr.InSleep || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded during sleep, actual %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+
+	err = repeater.RepeatErr(ctx, repeater.ConstantProgression(0), func(context.Context) error {
+		cancel()
+
+		return io.ErrUnexpectedEOF
+	}, 2)
+
+	if !errors.As(err, &repeatErr) || !repeatErr.Canceled() || repeatErr.InSleep {
+		t.Fatalf("expected cancellation during attempt, actual %v", err)
+	}
+}
+
+func Test_Error_Aborted(t *testing.T) {
+	t.Parallel()
+
+	err := repeater.RepeatErr(context.Background(), repeater.ConstantProgression(0), func(context.Context) error {
+		return repeater.Abort(io.ErrUnexpectedEOF)
+	}, 3)
+
+	repeatErr := &repeater.Error{}
+	if !errors.As(err, &repeatErr) || !repeatErr.Aborted || repeatErr.Exceeded || repeatErr.Context != nil {
+		t.Fatalf("expected aborted *repeater.Error, actual %+v", err)
+	}
+
+	expected := "repeat gave up after 1 attempts in " + repeatErr.Elapsed.String() + ", aborted: unexpected EOF"
+	if err.Error() != expected {
+		t.Fatalf("wrong error message, expected %q, actual %q", expected, err.Error())
+	}
+}
@@ -0,0 +1,171 @@
+package repeater
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type AttemptEventKind uint8
+
+const (
+	// AttemptStarted is sent right before the repeat func is called
+	AttemptStarted AttemptEventKind = iota + 1
+	// AttemptFinished is sent right after the repeat func returns,
+	// AttemptEvent.Finished holds its result
+	AttemptFinished
+	// RetryScheduled is sent before sleeping, AttemptEvent.Delay holds sleep duration
+	RetryScheduled
+	// RepeatFinished is the last event of every repeat,
+	// AttemptEvent.Finished holds the repeat result,
+	// AttemptEvent.Exhausted is set when all retries were used,
+	// AttemptEvent.Stopped is set when the repeat func returned a Stop error
+	RepeatFinished
+)
+
+func (k AttemptEventKind) String() string {
+	switch k {
+	case AttemptStarted:
+		return "attempt started"
+	case AttemptFinished:
+		return "attempt finished"
+	case RetryScheduled:
+		return "retry scheduled"
+	case RepeatFinished:
+		return "repeat finished"
+	default:
+		return "unknown"
+	}
+}
+
+type AttemptEvent struct {
+	Kind AttemptEventKind
+	// zero attempt is the initial call, retries start from 1
+	Attempt uint64
+	// time since the repeat start measured by the monotonic repeat clock
+	Elapsed   time.Duration
+	Delay     time.Duration
+	Finished  bool
+	Exhausted bool
+	Stopped   bool
+	Time      time.Time
+}
+
+type EventOverflow uint8
+
+const (
+	// DropEvents discards events which can't be sent to the channel immediately
+	DropEvents EventOverflow = iota
+	// BufferEvents keeps events which can't be sent to the channel immediately
+	// in memory and sends them from a separate goroutine preserving the order.
+	// At most 1024 events are kept, the rest are dropped. The goroutine exits when the buffer is drained,
+	// events of a repeat whose context is done are dropped, so a consumer may stop reading
+	// the channel only after the contexts of its repeats are done
+	BufferEvents
+)
+
+// maxBufferedEvents limits events kept by BufferEvents
+const maxBufferedEvents = 1024
+
+// WithEventChannel sends an AttemptEvent to ch for every step of the repeat loop.
+// The loop never blocks on ch, events that don't fit are handled by overflow.
+func WithEventChannel(ch chan<- AttemptEvent, overflow EventOverflow) Option {
+	return WithRecorder(&eventSender{
+		ch:       ch,
+		overflow: overflow,
+	})
+}
+
+type eventSender struct {
+	ch       chan<- AttemptEvent
+	overflow EventOverflow
+
+	mu       sync.Mutex
+	pending  []pendingEvent
+	flushing bool
+}
+
+// pendingEvent is a buffered event with the context of its repeat
+type pendingEvent struct {
+	ctx   context.Context
+	event AttemptEvent
+}
+
+func (s *eventSender) send(ctx context.Context, event AttemptEvent) {
+	if s.overflow == DropEvents {
+		select {
+		case s.ch <- event:
+		default:
+		}
+
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.flushing {
+		select {
+		case s.ch <- event:
+			return
+		default:
+		}
+
+		s.flushing = true
+
+		go s.flush()
+	}
+
+	if len(s.pending) < maxBufferedEvents {
+		s.pending = append(s.pending, pendingEvent{ctx: ctx, event: event})
+	}
+}
+
+func (s *eventSender) flush() {
+	for {
+		s.mu.Lock()
+
+		if len(s.pending) == 0 {
+			s.flushing = false
+			s.mu.Unlock()
+
+			return
+		}
+
+		pending := s.pending[0]
+		s.pending = s.pending[1:]
+
+		s.mu.Unlock()
+
+		select {
+		case s.ch <- pending.event:
+		case <-pending.ctx.Done():
+		}
+	}
+}
+
+func (s *eventSender) AttemptStarted(ctx context.Context, attempt uint64, elapsed time.Duration) {
+	s.send(ctx, AttemptEvent{Kind: AttemptStarted, Attempt: attempt, Elapsed: elapsed, Time: time.Now()})
+}
+
+func (s *eventSender) AttemptFinished(ctx context.Context, attempt uint64, elapsed time.Duration, finished bool) {
+	now := time.Now()
+
+	s.send(ctx, AttemptEvent{Kind: AttemptFinished, Attempt: attempt, Elapsed: elapsed, Finished: finished, Time: now})
+
+	if finished {
+		s.send(ctx, AttemptEvent{Kind: RepeatFinished, Attempt: attempt, Elapsed: elapsed, Finished: true, Time: now})
+	}
+}
+
+func (s *eventSender) RetryScheduled(ctx context.Context, attempt uint64, elapsed, delay time.Duration) {
+	s.send(ctx, AttemptEvent{Kind: RetryScheduled, Attempt: attempt, Elapsed: elapsed, Delay: delay, Time: time.Now()})
+}
+
+func (s *eventSender) GaveUp(ctx context.Context, attempt uint64, elapsed time.Duration, exhausted bool) {
+	s.send(ctx, AttemptEvent{Kind: RepeatFinished, Attempt: attempt, Elapsed: elapsed, Exhausted: exhausted, Time: time.Now()})
+}
+
+func (s *eventSender) Stopped(ctx context.Context, attempt uint64, elapsed time.Duration) {
+	s.send(ctx, AttemptEvent{Kind: RepeatFinished, Attempt: attempt, Elapsed: elapsed, Stopped: true, Time: time.Now()})
+}
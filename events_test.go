@@ -0,0 +1,193 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func collectEvents(ch <-chan repeater.AttemptEvent, count int) []repeater.AttemptEvent {
+	events := make([]repeater.AttemptEvent, 0, count)
+
+	timer := time.NewTimer(time.Second)
+	defer timer.Stop()
+
+	for range count {
+		select {
+		case event := <-ch:
+			events = append(events, event)
+		case <-timer.C:
+			return events
+		}
+	}
+
+	return events
+}
+
+func eventKinds(events []repeater.AttemptEvent) []repeater.AttemptEventKind {
+	kinds := make([]repeater.AttemptEventKind, 0, len(events))
+
+	for _, event := range events {
+		kinds = append(kinds, event.Kind)
+	}
+
+	return kinds
+}
+
+func Test_WithEventChannel(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan repeater.AttemptEvent, 16)
+
+	rp := repeater.New(
+		repeater.ConstantProgression(time.Millisecond),
+		repeater.WithEventChannel(ch, repeater.DropEvents),
+	)
+
+	calls := 0
+
+	finished := rp.Repeat(func() bool {
+		calls++
+
+		return calls == 2
+	}, 3)
+	if !finished {
+		t.Fatal("expected finished repeat")
+	}
+
+	events := collectEvents(ch, 6)
+
+	expectedKinds := []repeater.AttemptEventKind{
+		repeater.AttemptStarted,
+		repeater.AttemptFinished,
+		repeater.RetryScheduled,
+		repeater.AttemptStarted,
+		repeater.AttemptFinished,
+		repeater.RepeatFinished,
+	}
+
+	if !slices.Equal(expectedKinds, eventKinds(events)) {
+		t.Fatalf("wrong event kinds, expected %v, actual %v", expectedKinds, eventKinds(events))
+	}
+
+	if events[2].Delay != time.Millisecond {
+		t.Fatalf("wrong scheduled delay, expected %s, actual %s", time.Millisecond, events[2].Delay)
+	}
+
+	last := events[len(events)-1]
+	if last.Attempt != 1 || !last.Finished {
+		t.Fatalf("wrong finish event, expected attempt 1 finished, actual attempt %d finished %t", last.Attempt, last.Finished)
+	}
+}
+
+func Test_WithEventChannel_Drop(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan repeater.AttemptEvent, 1)
+
+	rp := repeater.New(
+		repeater.ConstantProgression(0),
+		repeater.WithEventChannel(ch, repeater.DropEvents),
+	)
+
+	finished := rp.Repeat(func() bool { return false }, 2)
+	if finished {
+		t.Fatal("expected not finished repeat")
+	}
+
+	event := <-ch
+	if event.Kind != repeater.AttemptStarted {
+		t.Fatalf("wrong first event kind, expected %s, actual %s", repeater.AttemptStarted, event.Kind)
+	}
+
+	select {
+	case event = <-ch:
+		t.Fatalf("unexpected event %s, overflowed events must be dropped", event.Kind)
+	default:
+	}
+}
+
+func Test_WithEventChannel_Buffer(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan repeater.AttemptEvent)
+
+	rp := repeater.New(
+		repeater.ConstantProgression(0),
+		repeater.WithEventChannel(ch, repeater.BufferEvents),
+	)
+
+	finished := rp.Repeat(func() bool { return false }, 1)
+	if finished {
+		t.Fatal("expected not finished repeat")
+	}
+
+	events := collectEvents(ch, 6)
+
+	expectedKinds := []repeater.AttemptEventKind{
+		repeater.AttemptStarted,
+		repeater.AttemptFinished,
+		repeater.RetryScheduled,
+		repeater.AttemptStarted,
+		repeater.AttemptFinished,
+		repeater.RepeatFinished,
+	}
+
+	if !slices.Equal(expectedKinds, eventKinds(events)) {
+		t.Fatalf("wrong event kinds, expected %v, actual %v", expectedKinds, eventKinds(events))
+	}
+}
+
+func Test_WithEventChannel_BufferLimit(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan repeater.AttemptEvent)
+
+	rp := repeater.New(
+		repeater.ConstantProgression(0),
+		repeater.WithEventChannel(ch, repeater.BufferEvents),
+	)
+
+	rp.Repeat(func() bool { return false }, 1000)
+
+	// one more event may be taken from the buffer by the sending goroutine
+	events := collectEvents(ch, 3000)
+	if len(events) < 1024 || len(events) > 1025 {
+		t.Fatalf("wrong buffered events count, expected 1024 or 1025, actual %d", len(events))
+	}
+
+	if events[0].Kind != repeater.AttemptStarted {
+		t.Fatalf("wrong first event kind, expected %s, actual %s", repeater.AttemptStarted, events[0].Kind)
+	}
+}
+
+func Test_WithEventChannel_BufferContextDone(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan repeater.AttemptEvent)
+
+	rp := repeater.New(
+		repeater.ConstantProgression(0),
+		repeater.WithEventChannel(ch, repeater.BufferEvents),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	errAttempt := errors.New("attempt failed")
+
+	_ = rp.RepeatErr(ctx, func(context.Context) error { return errAttempt }, 2)
+
+	cancel()
+
+	time.Sleep(time.Millisecond * 50)
+
+	select {
+	case event := <-ch:
+		t.Fatalf("unexpected event %s, events of done repeats must be dropped", event.Kind)
+	case <-time.After(time.Millisecond * 50):
+	}
+}
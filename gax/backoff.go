@@ -0,0 +1,54 @@
+package gaxrepeater
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+// Backoff holds the exported fields of github.com/googleapis/gax-go/v2.Backoff, it can't be converted
+// with a type conversion because of the unexported gax field, so copy the fields:
+//
+//	gax.Backoff{Initial: b.Initial, Max: b.Max, Multiplier: b.Multiplier}
+//
+// gax.CallOption values are not built by this package, it would need to depend on gax,
+// pass the backoff to gax.WithRetry with gax.OnCodes or use Retryer instead
+type Backoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+}
+
+// FromConfig converts a constant or exponential repeater config to Backoff.
+// gax.Backoff always sleeps a random duration up to the current one, so the config jitter is dropped,
+// max retries and max elapsed are dropped too because gax retries until the call context is done.
+// Configs without cap get the max duration as Max, because gax replaces zero Max with 30s
+func FromConfig(cfg repeater.Config) (Backoff, error) {
+	err := cfg.Validate()
+	if err != nil {
+		return Backoff{}, err
+	}
+
+	b := Backoff{
+		Initial:    cfg.Initial,
+		Max:        cfg.Cap,
+		Multiplier: 1,
+	}
+
+	switch cfg.Backoff {
+	case repeater.ConstantBackoff:
+		b.Max = cfg.Initial
+	case repeater.ExponentialBackoff:
+		b.Multiplier = cfg.Factor
+	default:
+		return Backoff{}, fmt.Errorf("%w: %q backoff can't be converted to gax.Backoff", repeater.ErrInvalidConfig, cfg.Backoff)
+	}
+
+	if b.Max == 0 {
+		b.Max = math.MaxInt64
+	}
+
+	return b, nil
+}
@@ -0,0 +1,47 @@
+package gaxrepeater_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	gaxrepeater "github.com/amidgo/repeater/gax"
+)
+
+func Test_FromConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		cfg      repeater.Config
+		expected gaxrepeater.Backoff
+	}{
+		{
+			cfg:      repeater.Config{Backoff: repeater.ExponentialBackoff, Initial: time.Millisecond * 10, Factor: 2, Cap: time.Second, Jitter: 0.5},
+			expected: gaxrepeater.Backoff{Initial: time.Millisecond * 10, Max: time.Second, Multiplier: 2},
+		},
+		{
+			cfg:      repeater.Config{Backoff: repeater.ExponentialBackoff, Initial: time.Millisecond * 10, Factor: 2},
+			expected: gaxrepeater.Backoff{Initial: time.Millisecond * 10, Max: math.MaxInt64, Multiplier: 2},
+		},
+		{
+			cfg:      repeater.Config{Backoff: repeater.ConstantBackoff, Initial: time.Second, MaxRetries: 3},
+			expected: gaxrepeater.Backoff{Initial: time.Second, Max: time.Second, Multiplier: 1},
+		},
+	} {
+		b, err := gaxrepeater.FromConfig(test.cfg)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if b != test.expected {
+			t.Fatalf("wrong backoff of %+v, expected %+v, actual %+v", test.cfg, test.expected, b)
+		}
+	}
+
+	_, err := gaxrepeater.FromConfig(repeater.Config{Backoff: repeater.FibonacciBackoff, Initial: time.Second})
+	if !errors.Is(err, repeater.ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, actual %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package gaxrepeater
+
+import (
+	"context"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+// Retryer implements github.com/googleapis/gax-go/v2.Retryer on top of repeater.Policy,
+// the interface is satisfied structurally, so the module doesn't depend on gax.
+// gax creates a Retryer for every call, so use it with
+// gax.WithRetry(func() gax.Retryer { return gaxrepeater.New(policy, isRetryable) }).
+// gax runs its own retry loop, so max elapsed of the policy is not used, retries and give ups
+// are reported to the policy recorders and stats, see WithRecorder. See Backoff for gax.Backoff
+type Retryer struct {
+	progression repeater.DurationProgression
+	retryCount  uint64
+	isRetryable func(err error) bool
+	recorders   []repeater.Recorder
+
+	attempt uint64
+	start   time.Time
+}
+
+type Option func(r *Retryer)
+
+// WithRecorder reports retries and give ups of the gax loop to recorder in addition to the policy recorders,
+// events are reported with the background context, attempts are not reported because gax doesn't expose them
+func WithRecorder(recorder repeater.Recorder) Option {
+	return func(r *Retryer) {
+		r.recorders = append(r.recorders, recorder)
+	}
+}
+
+// New returns Retryer retrying errors accepted by isRetryable with the policy progression and retry count,
+// e.g. a func checking status.Code(err) == codes.Unavailable
+func New(policy repeater.Policy, isRetryable func(err error) bool, opts ...Option) *Retryer {
+	r := &Retryer{
+		progression: policy.Repeater().Progression(),
+		retryCount:  policy.RetryCount(),
+		isRetryable: isRetryable,
+		recorders:   []repeater.Recorder{policy.Repeater().Recorder()},
+		start:       time.Now(),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Retry reports whether the failed call should be retried after pause
+func (r *Retryer) Retry(err error) (pause time.Duration, shouldRetry bool) {
+	exhausted := r.attempt >= r.retryCount
+
+	if exhausted || !r.isRetryable(err) {
+		for _, recorder := range r.recorders {
+			recorder.GaveUp(context.Background(), r.attempt, time.Since(r.start), exhausted)
+		}
+
+		return 0, false
+	}
+
+	pause = r.progression.Duration(r.attempt)
+	r.attempt++
+
+	for _, recorder := range r.recorders {
+		recorder.RetryScheduled(context.Background(), r.attempt, time.Since(r.start), pause)
+	}
+
+	return pause, true
+}
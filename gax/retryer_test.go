@@ -0,0 +1,76 @@
+package gaxrepeater_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	gaxrepeater "github.com/amidgo/repeater/gax"
+	"github.com/amidgo/repeater/repeatertest"
+)
+
+// gaxRetryer is a copy of github.com/googleapis/gax-go/v2.Retryer
+type gaxRetryer interface {
+	Retry(err error) (pause time.Duration, shouldRetry bool)
+}
+
+var _ gaxRetryer = (*gaxrepeater.Retryer)(nil)
+
+func Test_Retryer(t *testing.T) {
+	t.Parallel()
+
+	errUnavailable := errors.New("unavailable")
+
+	policy := repeater.NewPolicy(
+		repeater.WithProgression(repeater.NewArifmeticProgression(time.Second, time.Second)),
+		repeater.WithMaxRetries(2),
+	)
+
+	recorder := &repeatertest.Recorder{}
+
+	retryer := gaxrepeater.New(policy, func(err error) bool { return errors.Is(err, errUnavailable) }, gaxrepeater.WithRecorder(recorder))
+
+	var pauses []time.Duration
+
+	for {
+		pause, ok := retryer.Retry(errUnavailable)
+		if !ok {
+			break
+		}
+
+		pauses = append(pauses, pause)
+	}
+
+	expected := []time.Duration{time.Second, time.Second * 2}
+	if !slices.Equal(expected, pauses) {
+		t.Fatalf("wrong pauses, expected %v, actual %v", expected, pauses)
+	}
+
+	if !slices.Equal(expected, recorder.Delays()) {
+		t.Fatalf("wrong recorded delays, expected %v, actual %v", expected, recorder.Delays())
+	}
+
+	giveUps := recorder.GiveUps()
+	if len(giveUps) != 1 || !giveUps[0].Exhausted {
+		t.Fatalf("expected one exhausted give up, actual %+v", giveUps)
+	}
+
+	retryer = gaxrepeater.New(policy, func(err error) bool { return errors.Is(err, errUnavailable) })
+
+	if _, ok := retryer.Retry(errors.New("permission denied")); ok {
+		t.Fatal("not retryable error is retried")
+	}
+
+	expectedStats := repeater.Stats{
+		Calls:      2,
+		Aborts:     1,
+		Exceeded:   1,
+		TotalSleep: time.Second * 3,
+	}
+
+	if stats := policy.Repeater().Stats(); stats != expectedStats {
+		t.Fatalf("wrong policy stats, expected %+v, actual %+v", expectedStats, stats)
+	}
+}
@@ -1,5 +1,5 @@
 module github.com/amidgo/repeater
 
-go 1.22
+go 1.23
 
 require github.com/amidgo/tester v0.0.7
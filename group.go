@@ -0,0 +1,79 @@
+package repeater
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Group runs repeat funcs concurrently like errgroup.Group, every func is repeated by its own policy
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	policy Policy
+
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+	// aborted is the error of the first aborted func
+	aborted error
+}
+
+// NewGroup returns Group running funcs with a context derived from ctx and policy unless GoPolicy is used,
+// the derived context is canceled when a func aborts its repeat or Wait returns
+func NewGroup(ctx context.Context, policy Policy) *Group {
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &Group{
+		ctx:    ctx,
+		cancel: cancel,
+		policy: policy,
+	}
+}
+
+// Go repeats rf with the group policy in a new goroutine
+func (g *Group) Go(rf RepeatErrFunc) {
+	g.GoPolicy(g.policy, rf)
+}
+
+// GoPolicy repeats rf with policy in a new goroutine
+func (g *Group) GoPolicy(policy Policy, rf RepeatErrFunc) {
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+
+		err := policy.RepeatErr(g.ctx, rf)
+		if err == nil || errors.Is(err, ErrStopped) {
+			return
+		}
+
+		g.mu.Lock()
+		defer g.mu.Unlock()
+
+		g.errs = append(g.errs, err)
+
+		repeatErr := &Error{}
+		if g.aborted == nil && errors.As(err, &repeatErr) && repeatErr.Aborted {
+			g.aborted = err
+			g.cancel()
+		}
+	}()
+}
+
+// Wait waits for all funcs, returns nil if all of them succeeded or stopped,
+// *Error of the first func which aborted its repeat, e.g. with an Abort error,
+// or joined *Error of every func which gave up in the order they gave up
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.aborted != nil {
+		return g.aborted
+	}
+
+	return errors.Join(g.errs...)
+}
@@ -0,0 +1,137 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"sync/atomic"
+	"testing"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Group(t *testing.T) {
+	t.Parallel()
+
+	policy := repeater.NewPolicy(repeater.WithMaxRetries(2))
+
+	t.Run("all finished", func(t *testing.T) {
+		t.Parallel()
+
+		g := repeater.NewGroup(context.Background(), policy)
+
+		var calls atomic.Uint64
+
+		for range 5 {
+			var attempts uint64
+
+			g.Go(func(context.Context) error {
+				calls.Add(1)
+
+				attempts++
+				if attempts < 3 {
+					return io.ErrUnexpectedEOF
+				}
+
+				return nil
+			})
+		}
+
+		err := g.Wait()
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if calls.Load() != 15 {
+			t.Fatalf("expected 15 calls, actual %d", calls.Load())
+		}
+	})
+
+	t.Run("gave up", func(t *testing.T) {
+		t.Parallel()
+
+		g := repeater.NewGroup(context.Background(), policy)
+
+		g.Go(func(context.Context) error { return nil })
+		g.GoPolicy(repeater.NewPolicy(repeater.WithMaxRetries(0)), func(context.Context) error { return io.ErrUnexpectedEOF })
+		g.Go(func(context.Context) error { return io.ErrClosedPipe })
+
+		err := g.Wait()
+
+		if !errors.Is(err, io.ErrUnexpectedEOF) || !errors.Is(err, io.ErrClosedPipe) {
+			t.Fatalf("expected joined errors of both failed funcs, actual %v", err)
+		}
+
+		var errs interface{ Unwrap() []error }
+		if !errors.As(err, &errs) || len(errs.Unwrap()) != 2 {
+			t.Fatalf("expected two joined errors, actual %v", err)
+		}
+
+		attempts := map[uint64]bool{}
+
+		for _, err := range errs.Unwrap() {
+			repeatErr := &repeater.Error{}
+			if !errors.As(err, &repeatErr) {
+				t.Fatalf("expected *repeater.Error, actual %v", err)
+			}
+
+			attempts[repeatErr.Attempts] = true
+		}
+
+		if !attempts[1] || !attempts[3] {
+			t.Fatalf("expected 1 and 3 attempts of failed funcs, actual %v", attempts)
+		}
+	})
+
+	t.Run("aborted", func(t *testing.T) {
+		t.Parallel()
+
+		errPermanent := errors.New("permanent")
+
+		g := repeater.NewGroup(context.Background(), repeater.NewPolicy(repeater.WithMaxRetries(math.MaxUint64)))
+
+		g.Go(func(ctx context.Context) error {
+			<-ctx.Done()
+
+			return ctx.Err()
+		})
+		g.Go(func(context.Context) error { return io.ErrUnexpectedEOF })
+		g.Go(func(context.Context) error { return repeater.Abort(errPermanent) })
+
+		err := g.Wait()
+
+		repeatErr := &repeater.Error{}
+		if !errors.As(err, &repeatErr) || !repeatErr.Aborted || repeatErr.Attempts != 1 {
+			t.Fatalf("expected aborted *repeater.Error, actual %v", err)
+		}
+
+		if !errors.Is(err, errPermanent) || errors.Is(err, context.Canceled) || errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("expected only the abort error, actual %v", err)
+		}
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		t.Parallel()
+
+		g := repeater.NewGroup(context.Background(), policy)
+
+		var calls atomic.Uint64
+
+		g.Go(func(context.Context) error {
+			calls.Add(1)
+
+			return repeater.Stop()
+		})
+		g.Go(func(context.Context) error { return nil })
+
+		err := g.Wait()
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if calls.Load() != 1 {
+			t.Fatalf("wrong calls, expected 1, actual %d", calls.Load())
+		}
+	})
+}
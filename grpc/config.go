@@ -0,0 +1,237 @@
+package grpcrepeater
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+// maxAttemptsLimit is the limit gRPC applies to maxAttempts of a retry policy
+const maxAttemptsLimit = 5
+
+// ServiceConfig is the retry related part of the gRPC service config JSON
+type ServiceConfig struct {
+	MethodConfig []MethodConfig `json:"methodConfig"`
+}
+
+type MethodConfig struct {
+	Name        []MethodName `json:"name"`
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+}
+
+// MethodName matches a method, an empty Method matches every method of Service,
+// an empty Service matches every method
+type MethodName struct {
+	Service string `json:"service,omitempty"`
+	Method  string `json:"method,omitempty"`
+}
+
+// RetryPolicy is the retryPolicy of the gRPC service config, durations are encoded like "0.5s"
+type RetryPolicy struct {
+	// attempts including the initial one, gRPC limits it to 5
+	MaxAttempts       int
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	BackoffMultiplier float64
+	// status code names, e.g. "UNAVAILABLE"
+	RetryableStatusCodes []string
+}
+
+type retryPolicyJSON struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+// ParseServiceConfig parses the service config JSON and validates its retry policies
+func ParseServiceConfig(data []byte) (ServiceConfig, error) {
+	var sc ServiceConfig
+
+	err := json.Unmarshal(data, &sc)
+	if err != nil {
+		return ServiceConfig{}, err
+	}
+
+	return sc, nil
+}
+
+// RetryPolicy returns the retry policy of the full method name, e.g. "/pkg.Service/Method",
+// the most specific method config wins like in gRPC
+func (s ServiceConfig) RetryPolicy(fullMethod string) (policy RetryPolicy, ok bool) {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+
+	best := -1
+
+	for _, mc := range s.MethodConfig {
+		if mc.RetryPolicy == nil {
+			continue
+		}
+
+		for _, name := range mc.Name {
+			specificity := name.match(service, method)
+			if specificity > best {
+				best, policy = specificity, *mc.RetryPolicy
+			}
+		}
+	}
+
+	return policy, best >= 0
+}
+
+// match returns -1 if n doesn't match, higher values for more specific names
+func (n MethodName) match(service, method string) int {
+	switch {
+	case n.Service == "" && n.Method == "":
+		return 0
+	case n.Service == service && n.Method == "":
+		return 1
+	case n.Service == service && n.Method == method:
+		return 2
+	default:
+		return -1
+	}
+}
+
+func (p *RetryPolicy) UnmarshalJSON(data []byte) error {
+	var v retryPolicyJSON
+
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+
+	policy := RetryPolicy{
+		MaxAttempts:          v.MaxAttempts,
+		BackoffMultiplier:    v.BackoffMultiplier,
+		RetryableStatusCodes: v.RetryableStatusCodes,
+	}
+
+	policy.InitialBackoff, err = parseDuration(v.InitialBackoff)
+	if err != nil {
+		return fmt.Errorf("%w: initialBackoff: %w", repeater.ErrInvalidConfig, err)
+	}
+
+	policy.MaxBackoff, err = parseDuration(v.MaxBackoff)
+	if err != nil {
+		return fmt.Errorf("%w: maxBackoff: %w", repeater.ErrInvalidConfig, err)
+	}
+
+	err = policy.Validate()
+	if err != nil {
+		return err
+	}
+
+	*p = policy
+
+	return nil
+}
+
+// Validate applies the gRPC requirements to the retry policy
+func (p RetryPolicy) Validate() error {
+	switch {
+	case p.MaxAttempts < 2:
+		return fmt.Errorf("%w: maxAttempts %d must be greater than 1", repeater.ErrInvalidConfig, p.MaxAttempts)
+	case p.InitialBackoff <= 0:
+		return fmt.Errorf("%w: initialBackoff must be positive", repeater.ErrInvalidConfig)
+	case p.MaxBackoff <= 0:
+		return fmt.Errorf("%w: maxBackoff must be positive", repeater.ErrInvalidConfig)
+	case p.BackoffMultiplier <= 0:
+		return fmt.Errorf("%w: backoffMultiplier must be positive", repeater.ErrInvalidConfig)
+	case len(p.RetryableStatusCodes) == 0:
+		return fmt.Errorf("%w: retryableStatusCodes must not be empty", repeater.ErrInvalidConfig)
+	}
+
+	for _, code := range p.RetryableStatusCodes {
+		if _, ok := statusCodes[strings.ToUpper(code)]; !ok {
+			return fmt.Errorf("%w: unknown status code %q", repeater.ErrInvalidConfig, code)
+		}
+	}
+
+	return nil
+}
+
+// Policy returns the policy of p, maxAttempts is limited to 5 and every delay is random
+// in [0, backoff] range like in gRPC, opts are passed to repeater.New
+func (p RetryPolicy) Policy(opts ...repeater.Option) repeater.Policy {
+	attempts := min(p.MaxAttempts, maxAttemptsLimit)
+
+	progression := fullJitterProgression{
+		progression: repeater.NewCappedProgression(
+			repeater.NewExponentialProgression(p.InitialBackoff, p.BackoffMultiplier),
+			p.MaxBackoff,
+		),
+	}
+
+	return repeater.NewPolicy(
+		repeater.WithProgression(progression),
+		repeater.WithMaxRetries(uint64(max(attempts-1, 0))),
+		repeater.WithRepeaterOptions(opts...),
+	)
+}
+
+// Retryable reports whether the numeric status code, e.g. uint32(codes.Unavailable), is retryable
+func (p RetryPolicy) Retryable(code uint32) bool {
+	return slices.ContainsFunc(p.RetryableStatusCodes, func(name string) bool {
+		c, ok := statusCodes[strings.ToUpper(name)]
+
+		return ok && c == code
+	})
+}
+
+// fullJitterProgression randomizes durations in [0, d] range
+type fullJitterProgression struct {
+	progression repeater.DurationProgression
+}
+
+func (f fullJitterProgression) Duration(attempt uint64) time.Duration {
+	d := f.progression.Duration(attempt)
+	if d <= 0 {
+		return 0
+	}
+
+	return rand.N(d + 1)
+}
+
+// parseDuration parses durations of the protobuf JSON encoding, i.e. seconds with the "s" suffix
+func parseDuration(s string) (time.Duration, error) {
+	seconds, ok := strings.CutSuffix(s, "s")
+	if !ok {
+		return 0, fmt.Errorf("duration %q has no s suffix", s)
+	}
+
+	f, err := strconv.ParseFloat(seconds, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(f * float64(time.Second)), nil
+}
+
+// statusCodes are the gRPC status codes by name
+var statusCodes = map[string]uint32{
+	"OK":                  0,
+	"CANCELLED":           1,
+	"UNKNOWN":             2,
+	"INVALID_ARGUMENT":    3,
+	"DEADLINE_EXCEEDED":   4,
+	"NOT_FOUND":           5,
+	"ALREADY_EXISTS":      6,
+	"PERMISSION_DENIED":   7,
+	"RESOURCE_EXHAUSTED":  8,
+	"FAILED_PRECONDITION": 9,
+	"ABORTED":             10,
+	"OUT_OF_RANGE":        11,
+	"UNIMPLEMENTED":       12,
+	"INTERNAL":            13,
+	"UNAVAILABLE":         14,
+	"DATA_LOSS":           15,
+	"UNAUTHENTICATED":     16,
+}
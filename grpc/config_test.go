@@ -0,0 +1,89 @@
+package grpcrepeater_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	grpcrepeater "github.com/amidgo/repeater/grpc"
+)
+
+const serviceConfig = `{
+	"methodConfig": [
+		{
+			"name": [{"service": "echo.Echo"}],
+			"retryPolicy": {
+				"maxAttempts": 4,
+				"initialBackoff": "0.1s",
+				"maxBackoff": "1s",
+				"backoffMultiplier": 2,
+				"retryableStatusCodes": ["UNAVAILABLE"]
+			}
+		},
+		{
+			"name": [{"service": "echo.Echo", "method": "Stream"}],
+			"retryPolicy": {
+				"maxAttempts": 10,
+				"initialBackoff": "1.5s",
+				"maxBackoff": "10s",
+				"backoffMultiplier": 1.5,
+				"retryableStatusCodes": ["unavailable", "RESOURCE_EXHAUSTED"]
+			}
+		}
+	]
+}`
+
+func Test_ParseServiceConfig(t *testing.T) {
+	t.Parallel()
+
+	sc, err := grpcrepeater.ParseServiceConfig([]byte(serviceConfig))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	policy, ok := sc.RetryPolicy("/echo.Echo/Unary")
+	if !ok || policy.MaxAttempts != 4 || policy.InitialBackoff != time.Millisecond*100 || policy.MaxBackoff != time.Second {
+		t.Fatalf("unexpected service retry policy %+v", policy)
+	}
+
+	if !policy.Retryable(14) || policy.Retryable(8) {
+		t.Fatal("wrong retryable status codes")
+	}
+
+	policy, ok = sc.RetryPolicy("/echo.Echo/Stream")
+	if !ok || policy.MaxAttempts != 10 || policy.InitialBackoff != time.Millisecond*1500 || !policy.Retryable(8) {
+		t.Fatalf("unexpected method retry policy %+v", policy)
+	}
+
+	if policy.Policy().RetryCount() != 4 {
+		t.Fatalf("max attempts is not limited to 5, retry count %d", policy.Policy().RetryCount())
+	}
+
+	progression := policy.Policy().Repeater().Progression()
+	for range 100 {
+		if d := progression.Duration(10); d < 0 || d > time.Second*10 {
+			t.Fatalf("delay %s out of [0, max backoff] range", d)
+		}
+	}
+
+	_, ok = sc.RetryPolicy("/other.Service/Method")
+	if ok {
+		t.Fatal("unexpected retry policy of other service")
+	}
+}
+
+func Test_ParseServiceConfig_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, data := range []string{
+		`{"methodConfig": [{"name": [{}], "retryPolicy": {"maxAttempts": 1, "initialBackoff": "1s", "maxBackoff": "1s", "backoffMultiplier": 2, "retryableStatusCodes": ["UNAVAILABLE"]}}]}`,
+		`{"methodConfig": [{"name": [{}], "retryPolicy": {"maxAttempts": 3, "initialBackoff": "1m", "maxBackoff": "1s", "backoffMultiplier": 2, "retryableStatusCodes": ["UNAVAILABLE"]}}]}`,
+		`{"methodConfig": [{"name": [{}], "retryPolicy": {"maxAttempts": 3, "initialBackoff": "1s", "maxBackoff": "1s", "backoffMultiplier": 2, "retryableStatusCodes": ["BROKEN"]}}]}`,
+	} {
+		_, err := grpcrepeater.ParseServiceConfig([]byte(data))
+		if !errors.Is(err, repeater.ErrInvalidConfig) {
+			t.Fatalf("expected ErrInvalidConfig for %s, actual %v", data, err)
+		}
+	}
+}
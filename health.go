@@ -0,0 +1,142 @@
+package repeater
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+type HealthState uint8
+
+const (
+	// HealthStarting means the looped func was not called yet
+	HealthStarting HealthState = iota
+	// HealthRunning means the looped func is running or its last call succeeded
+	HealthRunning
+	// HealthFailing means the last call of the looped func failed and it waits for a retry
+	HealthFailing
+	// HealthStopped means the loop ended
+	HealthStopped
+)
+
+func (s HealthState) String() string {
+	switch s {
+	case HealthStarting:
+		return "starting"
+	case HealthRunning:
+		return "running"
+	case HealthFailing:
+		return "failing"
+	case HealthStopped:
+		return "stopped"
+	default:
+		return "unknown"
+	}
+}
+
+// Health is a snapshot of a loop or a supervised func state
+type Health struct {
+	State HealthState
+	// error of the last failed call, nil until the first failure
+	LastError error
+	// number of failed calls since the last success or progression reset
+	ConsecutiveFailures uint64
+	// time of the last successful call, zero if there was none
+	LastSuccess time.Time
+}
+
+// Healthy reports whether the loop is running without failures
+func (h Health) Healthy() bool {
+	return h.State == HealthRunning
+}
+
+func (h Health) MarshalJSON() ([]byte, error) {
+	v := struct {
+		State               string     `json:"state"`
+		Healthy             bool       `json:"healthy"`
+		LastError           string     `json:"last_error,omitempty"`
+		ConsecutiveFailures uint64     `json:"consecutive_failures"`
+		LastSuccess         *time.Time `json:"last_success,omitempty"`
+	}{
+		State:               h.State.String(),
+		Healthy:             h.Healthy(),
+		ConsecutiveFailures: h.ConsecutiveFailures,
+	}
+
+	if h.LastError != nil {
+		v.LastError = h.LastError.Error()
+	}
+
+	if !h.LastSuccess.IsZero() {
+		v.LastSuccess = &h.LastSuccess
+	}
+
+	return json.Marshal(v)
+}
+
+// HealthMonitor tracks Health of a Loop, see WithHealthMonitor, it is safe for concurrent use
+type HealthMonitor struct {
+	mu     sync.Mutex
+	health Health
+}
+
+func NewHealthMonitor() *HealthMonitor {
+	return &HealthMonitor{}
+}
+
+// WithHealthMonitor makes Loop report its state to m
+func WithHealthMonitor(m *HealthMonitor) LoopOption {
+	return func(o *loopOptions) {
+		o.health = m
+	}
+}
+
+// Health returns the current snapshot
+func (m *HealthMonitor) Health() Health {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.health
+}
+
+func (m *HealthMonitor) update(f func(h *Health)) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	f(&m.health)
+	m.mu.Unlock()
+}
+
+func (m *HealthMonitor) started() {
+	m.update(func(h *Health) {
+		h.State = HealthRunning
+	})
+}
+
+func (m *HealthMonitor) succeeded() {
+	m.update(func(h *Health) {
+		h.State = HealthRunning
+		h.ConsecutiveFailures = 0
+		h.LastSuccess = time.Now()
+	})
+}
+
+func (m *HealthMonitor) failed(err error, reset bool) {
+	m.update(func(h *Health) {
+		if reset {
+			h.ConsecutiveFailures = 0
+		}
+
+		h.State = HealthFailing
+		h.LastError = err
+		h.ConsecutiveFailures++
+	})
+}
+
+func (m *HealthMonitor) stopped() {
+	m.update(func(h *Health) {
+		h.State = HealthStopped
+	})
+}
@@ -0,0 +1,91 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Supervisor_Health(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+
+	release := make(chan struct{})
+
+	s := repeater.Supervise(context.Background(), repeater.ConstantProgression(time.Millisecond), func(ctx context.Context) error {
+		switch calls.Add(1) {
+		case 1, 2:
+			return io.ErrUnexpectedEOF
+		case 3:
+			panic("boom")
+		default:
+			<-release
+
+			return nil
+		}
+	})
+
+	for calls.Load() < 4 {
+		time.Sleep(time.Millisecond)
+	}
+
+	health := s.Health()
+
+	panicErr := &repeater.PanicError{}
+	if !errors.As(health.LastError, &panicErr) || panicErr.Value != "boom" {
+		t.Fatalf("expected panic error, actual %v", health.LastError)
+	}
+
+	if health.State != repeater.HealthRunning || health.ConsecutiveFailures != 3 {
+		t.Fatalf("unexpected health %+v", health)
+	}
+
+	close(release)
+
+	err := s.Wait()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	health = s.Health()
+	if health.State != repeater.HealthStopped || health.ConsecutiveFailures != 0 || health.LastSuccess.IsZero() {
+		t.Fatalf("unexpected health %+v", health)
+	}
+}
+
+func Test_Loop_WithHealthMonitor(t *testing.T) {
+	t.Parallel()
+
+	monitor := repeater.NewHealthMonitor()
+
+	if monitor.Health().State != repeater.HealthStarting {
+		t.Fatalf("wrong initial state %s", monitor.Health().State)
+	}
+
+	calls := 0
+
+	repeater.Loop(context.Background(), repeater.ConstantProgression(0), func(context.Context) error {
+		calls++
+
+		if calls == 1 {
+			return io.ErrUnexpectedEOF
+		}
+
+		if health := monitor.Health(); health.State != repeater.HealthRunning || health.ConsecutiveFailures != 1 {
+			t.Fatalf("unexpected health during a retry %+v", health)
+		}
+
+		return repeater.Abort(io.EOF)
+	}, repeater.WithHealthMonitor(monitor))
+
+	health := monitor.Health()
+	if health.State != repeater.HealthStopped || !errors.Is(health.LastError, io.EOF) || health.Healthy() {
+		t.Fatalf("unexpected health after abort %+v", health)
+	}
+}
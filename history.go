@@ -0,0 +1,78 @@
+package repeater
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithErrorHistory makes RepeatErr keep errors of failed attempts in Error.History,
+// only the first keep and the last keep errors are retained and the rest are counted in Error.Omitted,
+// so long repeats don't build large error chains, zero keep disables the history
+func WithErrorHistory(keep int) Option {
+	return func(r *Repeater) {
+		r.historyKeep = keep
+	}
+}
+
+// errorHistory retains the first keep errors and the last keep errors in a ring
+type errorHistory struct {
+	keep    int
+	first   []error
+	last    []error
+	next    int
+	omitted uint64
+}
+
+func (h *errorHistory) add(err error) {
+	if len(h.first) < h.keep {
+		h.first = append(h.first, err)
+
+		return
+	}
+
+	if len(h.last) < h.keep {
+		h.last = append(h.last, err)
+
+		return
+	}
+
+	h.last[h.next] = err
+	h.next = (h.next + 1) % h.keep
+	h.omitted++
+}
+
+// errors returns retained errors in attempt order
+func (h *errorHistory) errors() []error {
+	errs := make([]error, 0, len(h.first)+len(h.last))
+
+	errs = append(errs, h.first...)
+	errs = append(errs, h.last[h.next:]...)
+	errs = append(errs, h.last[:h.next]...)
+
+	return errs
+}
+
+// HistoryString formats Error.History with the omitted errors marker between the first and the last errors
+func (e *Error) HistoryString() string {
+	if len(e.History) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+
+	half := len(e.History) / 2
+
+	for i, err := range e.History {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+
+		if e.Omitted > 0 && i == half {
+			fmt.Fprintf(&b, "%d errors omitted; ", e.Omitted)
+		}
+
+		b.WriteString(err.Error())
+	}
+
+	return b.String()
+}
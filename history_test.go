@@ -0,0 +1,55 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_RepeatErr_WithErrorHistory(t *testing.T) {
+	t.Parallel()
+
+	rp := repeater.New(repeater.ConstantProgression(0), repeater.WithErrorHistory(2))
+
+	calls := 0
+
+	err := rp.RepeatErr(context.Background(), func(context.Context) error {
+		calls++
+
+		return fmt.Errorf("attempt %d", calls)
+	}, 6)
+
+	repeatErr := &repeater.Error{}
+	if !errors.As(err, &repeatErr) {
+		t.Fatalf("expected *repeater.Error, actual %v", err)
+	}
+
+	if len(repeatErr.History) != 4 || repeatErr.Omitted != 3 {
+		t.Fatalf("wrong history, expected 4 errors and 3 omitted, actual %v and %d omitted", repeatErr.History, repeatErr.Omitted)
+	}
+
+	expected := "attempt 1; attempt 2; 3 errors omitted; attempt 6; attempt 7"
+	if repeatErr.HistoryString() != expected {
+		t.Fatalf("wrong history string, expected %q, actual %q", expected, repeatErr.HistoryString())
+	}
+
+	calls = 0
+
+	err = rp.RepeatErr(context.Background(), func(context.Context) error {
+		calls++
+
+		return fmt.Errorf("attempt %d", calls)
+	}, 2)
+
+	if !errors.As(err, &repeatErr) {
+		t.Fatalf("expected *repeater.Error, actual %v", err)
+	}
+
+	expected = "attempt 1; attempt 2; attempt 3"
+	if repeatErr.HistoryString() != expected || repeatErr.Omitted != 0 {
+		t.Fatalf("wrong history string, expected %q, actual %q", expected, repeatErr.HistoryString())
+	}
+}
@@ -0,0 +1,56 @@
+package httprepeater_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/amidgo/repeater"
+	httprepeater "github.com/amidgo/repeater/http"
+)
+
+func Benchmark_Do_FirstAttemptSuccess(b *testing.B) {
+	resp := &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return resp, nil
+		}),
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	rp := httprepeater.New(repeater.New(repeater.ConstantProgression(0)))
+
+	b.ReportAllocs()
+
+	for range b.N {
+		_, _ = rp.Do(client, req, 3)
+	}
+}
+
+func Benchmark_Do_TransportErrorRetries(b *testing.B) {
+	errConnReset := errors.New("connection reset")
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errConnReset
+		}),
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	rp := httprepeater.New(repeater.New(repeater.ConstantProgression(0)))
+
+	b.ReportAllocs()
+
+	for range b.N {
+		_, _ = rp.Do(client, req, 3)
+	}
+}
@@ -0,0 +1,292 @@
+package httprepeater
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StaleHeader is set to "true" on cached responses returned by Do when retries were exhausted
+const StaleHeader = "X-Repeater-Stale"
+
+const (
+	// DefaultMaxCachedBody is the max size of a body stored by WithStaleCache unless WithMaxCachedBody is used
+	DefaultMaxCachedBody = 1 << 20
+	// DefaultMaxCacheEntries is the max number of responses kept by MemoryCache unless WithMaxEntries is used
+	DefaultMaxCacheEntries = 1024
+)
+
+// CachedResponse is a successful response stored by a Cache
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	StoredAt   time.Time
+	// VaryHeader holds the request headers named by the Vary response header,
+	// the response is served only to requests with the same values
+	VaryHeader http.Header
+}
+
+// Cache stores successful GET responses by request key, implementations must be safe for concurrent use
+type Cache interface {
+	Get(key string) (CachedResponse, bool)
+	Set(key string, resp CachedResponse)
+}
+
+// WithStaleCache stores bodies of successful GET responses in cache, when retries of a GET request are exhausted
+// Do returns the stored response with StaleHeader instead of the failure.
+// Requests with Authorization or Cookie headers are cached under keys of their credentials,
+// so responses are never served to other users, responses with "Vary: *" or
+// Cache-Control no-store or private directives are not cached
+func WithStaleCache(cache Cache) Option {
+	return func(r *Repeater) {
+		r.cache = cache
+	}
+}
+
+// WithMaxCachedBody limits the size of bodies stored by WithStaleCache, larger responses are not cached,
+// DefaultMaxCachedBody is used by default
+func WithMaxCachedBody(size int64) Option {
+	return func(r *Repeater) {
+		r.maxCachedBody = size
+	}
+}
+
+// IsStale reports whether resp was returned from the stale cache
+func IsStale(resp *http.Response) bool {
+	return resp != nil && resp.Header.Get(StaleHeader) == "true"
+}
+
+// MemoryCache is an in-memory Cache evicting least recently used responses
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List
+	responses  map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key  string
+	resp CachedResponse
+}
+
+type MemoryCacheOption func(m *MemoryCache)
+
+// WithMaxEntries sets the max number of responses kept by MemoryCache, DefaultMaxCacheEntries by default
+func WithMaxEntries(maxEntries int) MemoryCacheOption {
+	return func(m *MemoryCache) {
+		m.maxEntries = maxEntries
+	}
+}
+
+func NewMemoryCache(opts ...MemoryCacheOption) *MemoryCache {
+	m := &MemoryCache{
+		maxEntries: DefaultMaxCacheEntries,
+		order:      list.New(),
+		responses:  make(map[string]*list.Element),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+func (m *MemoryCache) Get(key string) (CachedResponse, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.responses[key]
+	if !ok {
+		return CachedResponse{}, false
+	}
+
+	m.order.MoveToFront(elem)
+
+	return elem.Value.(*memoryCacheEntry).resp, true
+}
+
+func (m *MemoryCache) Set(key string, resp CachedResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.responses[key]; ok {
+		elem.Value.(*memoryCacheEntry).resp = resp
+		m.order.MoveToFront(elem)
+
+		return
+	}
+
+	m.responses[key] = m.order.PushFront(&memoryCacheEntry{key: key, resp: resp})
+
+	for m.order.Len() > max(m.maxEntries, 1) {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.responses, oldest.Value.(*memoryCacheEntry).key)
+	}
+}
+
+// credentialHeaders separate cache entries of different users
+var credentialHeaders = []string{"Authorization", "Cookie"}
+
+// cacheKey is the request URL followed by the hash of the request credentials, if there are any
+func cacheKey(req *http.Request) string {
+	hash := sha256.New()
+	hasCredentials := false
+
+	for _, key := range credentialHeaders {
+		for _, value := range req.Header.Values(key) {
+			hasCredentials = true
+
+			_, _ = io.WriteString(hash, key+": "+value+"\n")
+		}
+	}
+
+	if !hasCredentials {
+		return req.URL.String()
+	}
+
+	return req.URL.String() + " " + hex.EncodeToString(hash.Sum(nil))
+}
+
+func cacheable(req *http.Request) bool {
+	return req.Method == http.MethodGet
+}
+
+func (r *Repeater) maxCachedBodySize() int64 {
+	if r.maxCachedBody > 0 {
+		return r.maxCachedBody
+	}
+
+	return DefaultMaxCachedBody
+}
+
+// storeResponse buffers the body of resp up to maxBody bytes and stores it in cache,
+// larger bodies are passed through without caching
+func storeResponse(cache Cache, maxBody int64, req *http.Request, resp *http.Response) (*http.Response, error) {
+	vary, ok := varyHeader(req, resp)
+	if !ok || !storable(resp) || resp.ContentLength > maxBody {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody+1))
+	if err != nil {
+		_ = resp.Body.Close()
+
+		return nil, err
+	}
+
+	if int64(len(body)) > maxBody {
+		resp.Body = readCloser{
+			Reader: io.MultiReader(bytes.NewReader(body), resp.Body),
+			Closer: resp.Body,
+		}
+
+		return resp, nil
+	}
+
+	_ = resp.Body.Close()
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	cache.Set(cacheKey(req), CachedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		StoredAt:   time.Now(),
+		VaryHeader: vary,
+	})
+
+	return resp, nil
+}
+
+// storable reports whether Cache-Control of resp allows to store it in a shared cache
+func storable(resp *http.Response) bool {
+	for _, value := range resp.Header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(directive), "=")
+
+			if strings.EqualFold(name, "no-store") || strings.EqualFold(name, "private") {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// varyHeader returns the request headers named by the Vary header of resp,
+// ok is false if the response varies on everything
+func varyHeader(req *http.Request, resp *http.Response) (vary http.Header, ok bool) {
+	for _, value := range resp.Header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+
+			switch name {
+			case "":
+				continue
+			case "*":
+				return nil, false
+			}
+
+			if vary == nil {
+				vary = http.Header{}
+			}
+
+			vary[http.CanonicalHeaderKey(name)] = req.Header.Values(name)
+		}
+	}
+
+	return vary, true
+}
+
+// varyMatches reports whether req has the same values of the headers cached response varies on
+func varyMatches(req *http.Request, cached CachedResponse) bool {
+	for name, values := range cached.VaryHeader {
+		if !slices.Equal(req.Header.Values(name), values) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func staleResponse(cache Cache, req *http.Request) (*http.Response, bool) {
+	cached, ok := cache.Get(cacheKey(req))
+	if !ok || !varyMatches(req, cached) {
+		return nil, false
+	}
+
+	header := cached.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	header.Set(StaleHeader, "true")
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", cached.StatusCode, http.StatusText(cached.StatusCode)),
+		StatusCode:    cached.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}, true
+}
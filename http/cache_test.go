@@ -0,0 +1,218 @@
+package httprepeater_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/amidgo/repeater"
+	httprepeater "github.com/amidgo/repeater/http"
+	"github.com/amidgo/repeater/http/httprepeatertest"
+)
+
+func Test_Do_WithStaleCache(t *testing.T) {
+	t.Parallel()
+
+	tr := httprepeatertest.NewTransport(t,
+		httprepeatertest.Reply{StatusCode: http.StatusOK, Header: http.Header{"Etag": {"v1"}}, Body: "cached"},
+		httprepeatertest.Reply{StatusCode: http.StatusServiceUnavailable},
+		httprepeatertest.Reply{StatusCode: http.StatusBadGateway},
+		httprepeatertest.Reply{StatusCode: http.StatusNotFound},
+	)
+
+	rp := httprepeater.New(
+		repeater.New(repeater.ConstantProgression(0)),
+		httprepeater.WithStaleCache(httprepeater.NewMemoryCache()),
+	)
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost/items", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := rp.Do(tr.Client(), req, 1)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "cached" || httprepeater.IsStale(resp) {
+		t.Fatalf("unexpected fresh response %q stale %t", body, httprepeater.IsStale(resp))
+	}
+
+	resp, err = rp.Do(tr.Client(), req, 1)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	body, _ = io.ReadAll(resp.Body)
+	if string(body) != "cached" || !httprepeater.IsStale(resp) || resp.Header.Get("Etag") != "v1" {
+		t.Fatalf("unexpected stale response %q %v", body, resp.Header)
+	}
+
+	if resp.Status != "200 OK" {
+		t.Fatalf("wrong stale status, expected %q, actual %q", "200 OK", resp.Status)
+	}
+
+	resp, err = rp.Do(tr.Client(), req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if resp.StatusCode != http.StatusNotFound || httprepeater.IsStale(resp) {
+		t.Fatalf("not retryable response must be returned as is, actual %d", resp.StatusCode)
+	}
+}
+
+func Test_Do_WithStaleCache_Credentials(t *testing.T) {
+	t.Parallel()
+
+	tr := httprepeatertest.NewTransport(t,
+		httprepeatertest.Reply{StatusCode: http.StatusOK, Body: "alice"},
+		httprepeatertest.Reply{StatusCode: http.StatusServiceUnavailable},
+		httprepeatertest.Reply{StatusCode: http.StatusOK, Header: http.Header{"Vary": {"Accept-Language"}}, Body: "english"},
+		httprepeatertest.Reply{StatusCode: http.StatusServiceUnavailable},
+	)
+
+	rp := httprepeater.New(
+		repeater.New(repeater.ConstantProgression(0)),
+		httprepeater.WithStaleCache(httprepeater.NewMemoryCache()),
+	)
+
+	newRequest := func(header http.Header) *http.Request {
+		req, err := http.NewRequest(http.MethodGet, "http://localhost/items", http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header = header
+
+		return req
+	}
+
+	resp, err := rp.Do(tr.Client(), newRequest(http.Header{"Authorization": {"Bearer alice"}}), 0)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected response %v, error %v", resp, err)
+	}
+
+	resp, err = rp.Do(tr.Client(), newRequest(http.Header{"Authorization": {"Bearer bob"}}), 0)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if httprepeater.IsStale(resp) || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("response of another user must not be served, actual %d stale %t", resp.StatusCode, httprepeater.IsStale(resp))
+	}
+
+	resp, err = rp.Do(tr.Client(), newRequest(http.Header{"Accept-Language": {"en"}}), 0)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected response %v, error %v", resp, err)
+	}
+
+	resp, err = rp.Do(tr.Client(), newRequest(http.Header{"Accept-Language": {"fr"}}), 0)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if httprepeater.IsStale(resp) {
+		t.Fatal("response varying on Accept-Language must not be served for another language")
+	}
+}
+
+func Test_Do_WithStaleCache_CacheControl(t *testing.T) {
+	t.Parallel()
+
+	for _, cacheControl := range []string{"no-store", "private, max-age=60", "max-age=60, No-Store"} {
+		t.Run(cacheControl, func(t *testing.T) {
+			t.Parallel()
+
+			tr := httprepeatertest.NewTransport(t,
+				httprepeatertest.Reply{StatusCode: http.StatusOK, Header: http.Header{"Cache-Control": {cacheControl}}, Body: "secret"},
+				httprepeatertest.Reply{StatusCode: http.StatusServiceUnavailable},
+			)
+
+			rp := httprepeater.New(
+				repeater.New(repeater.ConstantProgression(0)),
+				httprepeater.WithStaleCache(httprepeater.NewMemoryCache()),
+			)
+
+			req, err := http.NewRequest(http.MethodGet, "http://localhost/items", http.NoBody)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := rp.Do(tr.Client(), req, 0)
+			if err != nil || resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected response %v, error %v", resp, err)
+			}
+
+			resp, err = rp.Do(tr.Client(), req, 0)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if httprepeater.IsStale(resp) {
+				t.Fatalf("response with Cache-Control %q must not be cached", cacheControl)
+			}
+		})
+	}
+}
+
+func Test_Do_WithMaxCachedBody(t *testing.T) {
+	t.Parallel()
+
+	tr := httprepeatertest.NewTransport(t,
+		httprepeatertest.Reply{StatusCode: http.StatusOK, Body: "too large"},
+		httprepeatertest.Reply{StatusCode: http.StatusServiceUnavailable},
+	)
+
+	rp := httprepeater.New(
+		repeater.New(repeater.ConstantProgression(0)),
+		httprepeater.WithStaleCache(httprepeater.NewMemoryCache()),
+		httprepeater.WithMaxCachedBody(4),
+	)
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost/items", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := rp.Do(tr.Client(), req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "too large" {
+		t.Fatalf("large body must be passed through, actual %q", body)
+	}
+
+	resp, err = rp.Do(tr.Client(), req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if httprepeater.IsStale(resp) {
+		t.Fatal("large body must not be cached")
+	}
+}
+
+func Test_MemoryCache_WithMaxEntries(t *testing.T) {
+	t.Parallel()
+
+	cache := httprepeater.NewMemoryCache(httprepeater.WithMaxEntries(2))
+
+	cache.Set("a", httprepeater.CachedResponse{StatusCode: http.StatusOK})
+	cache.Set("b", httprepeater.CachedResponse{StatusCode: http.StatusOK})
+
+	// a becomes the most recently used
+	cache.Get("a")
+
+	cache.Set("c", httprepeater.CachedResponse{StatusCode: http.StatusOK})
+
+	for key, expected := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok := cache.Get(key); ok != expected {
+			t.Fatalf("wrong presence of %q, expected %t, actual %t", key, expected, ok)
+		}
+	}
+}
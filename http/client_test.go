@@ -0,0 +1,297 @@
+package httprepeater_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	httprepeater "github.com/amidgo/repeater/http"
+)
+
+func Test_Do_CookieJar(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "42"})
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		cookie, err := r.Cookie("session")
+		if err != nil || cookie.Value != "42" {
+			t.Errorf("cookie is not sent on retry: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := httprepeater.Do(repeater.New(repeater.ConstantProgression(0)), &http.Client{Jar: jar}, req, 2)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls.Load() != 2 {
+		t.Fatalf("unexpected response %d after %d calls", resp.StatusCode, calls.Load())
+	}
+}
+
+func Test_Do_Redirect(t *testing.T) {
+	t.Parallel()
+
+	var redirects, targets atomic.Int64
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		redirects.Add(1)
+
+		http.Redirect(w, r, "/target", http.StatusFound)
+	})
+	mux.HandleFunc("/target", func(w http.ResponseWriter, _ *http.Request) {
+		if targets.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/redirect", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := httprepeater.Do(repeater.New(repeater.ConstantProgression(0)), srv.Client(), req, 2)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected response %d", resp.StatusCode)
+	}
+
+	if redirects.Load() != 2 || targets.Load() != 2 {
+		t.Fatalf("every attempt must follow the redirect once, actual %d redirects and %d targets", redirects.Load(), targets.Load())
+	}
+}
+
+func Test_Do_Body(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("unexpected body %q", body)
+		}
+
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	rp := repeater.New(repeater.ConstantProgression(0))
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := httprepeater.Do(rp, srv.Client(), req, 2)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls.Load() != 2 {
+		t.Fatalf("unexpected response %d after %d calls", resp.StatusCode, calls.Load())
+	}
+
+	calls.Store(0)
+
+	req, err = http.NewRequest(http.MethodPost, srv.URL, io.NopCloser(strings.NewReader("payload")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err = httprepeater.Do(rp, srv.Client(), req, 2)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable || calls.Load() != 1 {
+		t.Fatalf("not replayable body must not be retried, actual %d response after %d calls", resp.StatusCode, calls.Load())
+	}
+}
+
+func Test_Do_RequestClonePerAttempt(t *testing.T) {
+	t.Parallel()
+
+	var attempts []uint64
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempt, ok := httprepeater.AttemptFromContext(req.Context())
+			if !ok {
+				t.Error("attempt is not found in request context")
+			}
+
+			attempts = append(attempts, attempt)
+
+			if req.Header.Get("X-Mutated") != "" {
+				t.Error("header mutated by previous attempt leaked")
+			}
+
+			req.Header.Set("X-Mutated", "true")
+
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil
+		}),
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = httprepeater.Do(repeater.New(repeater.ConstantProgression(0)), client, req, 2)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if !slices.Equal([]uint64{0, 1, 2}, attempts) {
+		t.Fatalf("wrong attempts %v", attempts)
+	}
+
+	if req.Header.Get("X-Mutated") != "" {
+		t.Fatal("original request header mutated")
+	}
+}
+
+type truncatedReader struct {
+	data string
+}
+
+func (t *truncatedReader) Read(p []byte) (int, error) {
+	if t.data == "" {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	n := copy(p, t.data)
+	t.data = t.data[n:]
+
+	return n, nil
+}
+
+func Test_Do_WithBodyVerification(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			calls++
+
+			if calls == 1 {
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(&truncatedReader{data: "par"})}, nil
+			}
+
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("payload"))}, nil
+		}),
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rp := httprepeater.New(repeater.New(repeater.ConstantProgression(0)), httprepeater.WithBodyVerification())
+
+	resp, err := rp.Do(client, req, 2)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(body) != "payload" || calls != 2 {
+		t.Fatalf("unexpected body %q after %d calls", body, calls)
+	}
+
+	client.Transport = roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(&truncatedReader{data: "par"})}, nil
+	})
+
+	_, err = rp.Do(client, req, 2)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected io.ErrUnexpectedEOF, actual %v", err)
+	}
+}
+
+func Test_Do_HTTP2GoAway(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			calls++
+
+			switch calls {
+			case 1:
+				return nil, errors.New("http2: server sent GOAWAY and closed the connection; LastStreamID=1, ErrCode=NO_ERROR, debug=\"\"")
+			case 2:
+				return nil, errors.New("stream error: stream ID 3; REFUSED_STREAM")
+			default:
+				return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+			}
+		}),
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+
+	resp, err := httprepeater.Do(repeater.New(repeater.ConstantProgression(time.Hour)), client, req, 2)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Fatalf("unexpected response %d after %d calls", resp.StatusCode, calls)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected immediate retries, elapsed %s", elapsed)
+	}
+}
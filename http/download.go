@@ -0,0 +1,111 @@
+package httprepeater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/amidgo/repeater"
+)
+
+// ErrUnexpectedRange is returned by a Download attempt when a partial response doesn't start at the requested offset
+var ErrUnexpectedRange = errors.New("unexpected content range")
+
+func Download(ctx context.Context, rp *repeater.Repeater, client *http.Client, url string, dst io.WriterAt, retryCount uint64) (written int64, err error) {
+	httpRp := Repeater{repeater: rp}
+
+	return httpRp.Download(ctx, client, url, dst, retryCount)
+}
+
+// Download writes the body of a GET request to url into dst, failed attempts are resumed from the last received byte
+// with Range requests validated by If-Range, so the server sends the whole body again if the resource changed.
+// Responses without a strong ETag or Last-Modified are not resumable and are downloaded again from the start.
+// Errors of writes to dst abort the download, only failed requests and body reads are retried.
+// Returns the size of the written body and *repeater.Error if the download gave up
+func (r *Repeater) Download(ctx context.Context, client *http.Client, url string, dst io.WriterAt, retryCount uint64) (written int64, err error) {
+	var validator string
+
+	err = r.repeater.RepeatErr(ctx, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+		if err != nil {
+			return repeater.Abort(err)
+		}
+
+		if written > 0 && validator != "" {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", written))
+			req.Header.Set("If-Range", validator)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			if shouldFinishRetry(nil, err) {
+				return repeater.Abort(err)
+			}
+
+			return err
+		}
+		defer resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			written = 0
+			validator = rangeValidator(resp)
+		case http.StatusPartialContent:
+			if !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", written)) {
+				written = 0
+
+				return ErrUnexpectedRange
+			}
+		default:
+			statusErr := &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+			if shouldFinishRetry(resp, nil) {
+				return repeater.Abort(statusErr)
+			}
+
+			return statusErr
+		}
+
+		w := &downloadWriter{w: io.NewOffsetWriter(dst, written)}
+
+		n, err := io.Copy(w, resp.Body)
+		written += n
+
+		if w.err != nil {
+			return repeater.Abort(w.err)
+		}
+
+		return err
+	}, retryCount)
+
+	return written, err
+}
+
+// downloadWriter keeps the write error, so it isn't retried as a failed body read
+type downloadWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (d *downloadWriter) Write(p []byte) (int, error) {
+	n, err := d.w.Write(p)
+	if err == nil && n < len(p) {
+		err = io.ErrShortWrite
+	}
+
+	d.err = err
+
+	return n, err
+}
+
+// rangeValidator returns the If-Range value of resp, If-Range doesn't accept weak ETags
+func rangeValidator(resp *http.Response) string {
+	etag := resp.Header.Get("ETag")
+	if etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+
+	return resp.Header.Get("Last-Modified")
+}
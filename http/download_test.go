@@ -0,0 +1,151 @@
+package httprepeater_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	httprepeater "github.com/amidgo/repeater/http"
+)
+
+func Test_Download(t *testing.T) {
+	t.Parallel()
+
+	content := strings.Repeat("0123456789", 1000)
+	modified := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var (
+		calls  atomic.Int64
+		ranges []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+
+		w.Header().Set("ETag", `"v1"`)
+
+		if calls.Add(1) == 1 {
+			w.Header().Set("Content-Length", "10000")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(content[:4000]))
+
+			panic(http.ErrAbortHandler)
+		}
+
+		http.ServeContent(w, r, "", modified, strings.NewReader(content))
+	}))
+	defer srv.Close()
+
+	dst, err := os.Create(filepath.Join(t.TempDir(), "download"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+
+	written, err := httprepeater.Download(context.Background(), repeater.New(repeater.ConstantProgression(0)), srv.Client(), srv.URL, dst, 2)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if written != int64(len(content)) {
+		t.Fatalf("wrong written size, expected %d, actual %d", len(content), written)
+	}
+
+	if len(ranges) != 2 || ranges[1] != "bytes=4000-" {
+		t.Fatalf("expected the second request resumes from 4000, actual ranges %q", ranges)
+	}
+
+	downloaded, err := os.ReadFile(dst.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(downloaded, []byte(content)) {
+		t.Fatal("downloaded content differs")
+	}
+}
+
+func Test_Download_ResourceChanged(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Content-Length", "10")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("old"))
+
+			panic(http.ErrAbortHandler)
+		}
+
+		w.Header().Set("ETag", `"v2"`)
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader("new content"))
+	}))
+	defer srv.Close()
+
+	dst, err := os.Create(filepath.Join(t.TempDir(), "download"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+
+	written, err := httprepeater.Download(context.Background(), repeater.New(repeater.ConstantProgression(0)), srv.Client(), srv.URL, dst, 2)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	downloaded, _ := os.ReadFile(dst.Name())
+	if written != 11 || string(downloaded) != "new content" {
+		t.Fatalf("expected the changed resource downloaded from the start, actual %q, written %d", downloaded, written)
+	}
+}
+
+type failingWriterAt struct {
+	err error
+}
+
+func (f failingWriterAt) WriteAt([]byte, int64) (int, error) {
+	return 0, f.err
+}
+
+func Test_Download_WriteError(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+
+		_, _ = w.Write([]byte("content"))
+	}))
+	defer srv.Close()
+
+	errDiskFull := errors.New("no space left on device")
+
+	_, err := httprepeater.Download(
+		context.Background(),
+		repeater.New(repeater.ConstantProgression(0)),
+		srv.Client(),
+		srv.URL,
+		failingWriterAt{err: errDiskFull},
+		2,
+	)
+	if !errors.Is(err, errDiskFull) || !repeater.IsAbort(err) {
+		t.Fatalf("expected aborted write error, actual %v", err)
+	}
+
+	if calls.Load() != 1 {
+		t.Fatalf("write error must not be retried, actual calls %d", calls.Load())
+	}
+}
@@ -0,0 +1,127 @@
+package httprepeater
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Envoy retry policy headers, see https://www.envoyproxy.io/docs/envoy/latest/configuration/http/http_filters/router_filter
+const (
+	EnvoyRetryOnHeader              = "X-Envoy-Retry-On"
+	EnvoyMaxRetriesHeader           = "X-Envoy-Max-Retries"
+	EnvoyRetriableStatusCodesHeader = "X-Envoy-Retriable-Status-Codes"
+)
+
+// Envoy retry-on conditions supported by WithEnvoyHeaders
+const (
+	envoyRetryOnFiveXX               = "5xx"
+	envoyRetryOnGatewayError         = "gateway-error"
+	envoyRetryOnReset                = "reset"
+	envoyRetryOnConnectFailure       = "connect-failure"
+	envoyRetryOnRefusedStream        = "refused-stream"
+	envoyRetryOnRetriable4xx         = "retriable-4xx"
+	envoyRetryOnRetriableStatusCodes = "retriable-status-codes"
+)
+
+// WithEnvoyHeaders makes Do honor Envoy retry policy headers of the request,
+// X-Envoy-Max-Retries lowers the retry count, it never raises the retry count passed to Do,
+// so clients can't amplify retries of forwarded requests. X-Envoy-Retry-On with X-Envoy-Retriable-Status-Codes
+// replace the classification of responses and errors. Supported retry-on conditions are
+// 5xx, gateway-error, reset, connect-failure, refused-stream, retriable-4xx and retriable-status-codes,
+// transport errors are retried by any of 5xx, gateway-error, reset, connect-failure and refused-stream
+func WithEnvoyHeaders() Option {
+	return func(r *Repeater) {
+		r.honorEnvoy = true
+	}
+}
+
+// WithEnvoyRetryPolicy sets Envoy retry policy headers on every attempt, so an upstream mesh
+// applies the same policy, retriable status codes are sent only if there are any
+func WithEnvoyRetryPolicy(retryOn []string, maxRetries uint64, retriableStatusCodes ...int) Option {
+	header := http.Header{}
+
+	header.Set(EnvoyRetryOnHeader, strings.Join(retryOn, ","))
+	header.Set(EnvoyMaxRetriesHeader, strconv.FormatUint(maxRetries, 10))
+
+	if len(retriableStatusCodes) > 0 {
+		codes := make([]string, 0, len(retriableStatusCodes))
+
+		for _, code := range retriableStatusCodes {
+			codes = append(codes, strconv.Itoa(code))
+		}
+
+		header.Set(EnvoyRetriableStatusCodesHeader, strings.Join(codes, ","))
+	}
+
+	return func(r *Repeater) {
+		r.envoyHeader = header
+	}
+}
+
+// envoyPolicy is the retry policy of Envoy request headers
+type envoyPolicy struct {
+	retryOn     []string
+	statusCodes []int
+}
+
+// envoyRetryCount returns X-Envoy-Max-Retries of req
+func envoyRetryCount(req *http.Request) (retryCount uint64, ok bool) {
+	retryCount, err := strconv.ParseUint(strings.TrimSpace(req.Header.Get(EnvoyMaxRetriesHeader)), 10, 64)
+
+	return retryCount, err == nil
+}
+
+// parseEnvoyPolicy returns the policy of X-Envoy-Retry-On and X-Envoy-Retriable-Status-Codes of req
+func parseEnvoyPolicy(req *http.Request) (envoyPolicy, bool) {
+	retryOn := req.Header.Get(EnvoyRetryOnHeader)
+	if retryOn == "" {
+		return envoyPolicy{}, false
+	}
+
+	var p envoyPolicy
+
+	for _, condition := range strings.Split(retryOn, ",") {
+		p.retryOn = append(p.retryOn, strings.TrimSpace(condition))
+	}
+
+	for _, code := range strings.Split(req.Header.Get(EnvoyRetriableStatusCodesHeader), ",") {
+		status, err := strconv.Atoi(strings.TrimSpace(code))
+		if err == nil {
+			p.statusCodes = append(p.statusCodes, status)
+		}
+	}
+
+	return p, true
+}
+
+func (p envoyPolicy) on(conditions ...string) bool {
+	return slices.ContainsFunc(p.retryOn, func(condition string) bool {
+		return slices.Contains(conditions, condition)
+	})
+}
+
+// classify has the signature of Repeater.classify, err is returned as is
+func (p envoyPolicy) classify(_ context.Context, resp *http.Response, err error) (finished bool, _ error) {
+	if err != nil {
+		retryable := p.on(envoyRetryOnFiveXX, envoyRetryOnGatewayError, envoyRetryOnReset, envoyRetryOnConnectFailure, envoyRetryOnRefusedStream)
+
+		return !retryable || shouldFinishRetry(nil, err), err
+	}
+
+	switch status := resp.StatusCode; {
+	case status >= 500 && p.on(envoyRetryOnFiveXX):
+		return false, nil
+	case (status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout) &&
+		p.on(envoyRetryOnGatewayError):
+		return false, nil
+	case status == http.StatusConflict && p.on(envoyRetryOnRetriable4xx):
+		return false, nil
+	case slices.Contains(p.statusCodes, status) && p.on(envoyRetryOnRetriableStatusCodes):
+		return false, nil
+	default:
+		return true, nil
+	}
+}
@@ -0,0 +1,148 @@
+package httprepeater_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/amidgo/repeater"
+	httprepeater "github.com/amidgo/repeater/http"
+	"github.com/amidgo/repeater/http/httprepeatertest"
+)
+
+func Test_Do_WithEnvoyHeaders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retriable status codes", func(t *testing.T) {
+		t.Parallel()
+
+		tr := httprepeatertest.NewTransport(t,
+			httprepeatertest.Reply{StatusCode: http.StatusConflict},
+			httprepeatertest.Reply{StatusCode: http.StatusTooEarly},
+			httprepeatertest.Reply{StatusCode: http.StatusOK},
+		)
+
+		rp := httprepeater.New(repeater.New(repeater.ConstantProgression(0)), httprepeater.WithEnvoyHeaders())
+
+		req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set(httprepeater.EnvoyRetryOnHeader, "retriable-4xx, retriable-status-codes")
+		req.Header.Set(httprepeater.EnvoyRetriableStatusCodesHeader, "425")
+		req.Header.Set(httprepeater.EnvoyMaxRetriesHeader, "2")
+
+		resp, err := rp.Do(tr.Client(), req, 5)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("wrong status, expected %d, actual %d", http.StatusOK, resp.StatusCode)
+		}
+	})
+
+	t.Run("max retries above retry count", func(t *testing.T) {
+		t.Parallel()
+
+		tr := httprepeatertest.NewTransport(t,
+			httprepeatertest.Reply{StatusCode: http.StatusServiceUnavailable},
+			httprepeatertest.Reply{StatusCode: http.StatusServiceUnavailable},
+		)
+
+		rp := httprepeater.New(repeater.New(repeater.ConstantProgression(0)), httprepeater.WithEnvoyHeaders())
+
+		req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set(httprepeater.EnvoyRetryOnHeader, "5xx")
+		req.Header.Set(httprepeater.EnvoyMaxRetriesHeader, "100")
+
+		resp, err := rp.Do(tr.Client(), req, 1)
+		if err != nil || resp.StatusCode != http.StatusServiceUnavailable || len(tr.Requests()) != 2 {
+			t.Fatalf("expected 503 after 2 requests, actual %v, %v after %d requests", resp, err, len(tr.Requests()))
+		}
+	})
+
+	t.Run("not retriable status", func(t *testing.T) {
+		t.Parallel()
+
+		tr := httprepeatertest.NewTransport(t,
+			httprepeatertest.Reply{StatusCode: http.StatusInternalServerError},
+		)
+
+		rp := httprepeater.New(repeater.New(repeater.ConstantProgression(0)), httprepeater.WithEnvoyHeaders())
+
+		req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set(httprepeater.EnvoyRetryOnHeader, "gateway-error")
+
+		resp, err := rp.Do(tr.Client(), req, 3)
+		if err != nil || resp.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("expected not retried 500, actual %v, %v", resp, err)
+		}
+	})
+
+	t.Run("transport errors", func(t *testing.T) {
+		t.Parallel()
+
+		errReset := errors.New("connection reset by peer")
+
+		tr := httprepeatertest.NewTransport(t,
+			httprepeatertest.Reply{Err: errReset},
+		)
+
+		rp := httprepeater.New(repeater.New(repeater.ConstantProgression(0)), httprepeater.WithEnvoyHeaders())
+
+		req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set(httprepeater.EnvoyRetryOnHeader, "retriable-4xx")
+
+		_, err = rp.Do(tr.Client(), req, 3)
+		if !errors.Is(err, errReset) || len(tr.Requests()) != 1 {
+			t.Fatalf("expected not retried transport error, actual %v after %d requests", err, len(tr.Requests()))
+		}
+	})
+}
+
+func Test_Do_WithEnvoyRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	tr := httprepeatertest.NewTransport(t, httprepeatertest.Reply{StatusCode: http.StatusOK})
+
+	rp := httprepeater.New(
+		repeater.New(repeater.ConstantProgression(0)),
+		httprepeater.WithEnvoyRetryPolicy([]string{"5xx", "retriable-status-codes"}, 2, 409, 425),
+	)
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = rp.Do(tr.Client(), req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	header := tr.Requests()[0].Header
+
+	if header.Get(httprepeater.EnvoyRetryOnHeader) != "5xx,retriable-status-codes" ||
+		header.Get(httprepeater.EnvoyMaxRetriesHeader) != "2" ||
+		header.Get(httprepeater.EnvoyRetriableStatusCodesHeader) != "409,425" {
+		t.Fatalf("unexpected envoy headers %v", header)
+	}
+
+	if req.Header.Get(httprepeater.EnvoyRetryOnHeader) != "" {
+		t.Fatal("envoy headers are set on the original request")
+	}
+}
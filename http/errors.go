@@ -0,0 +1,25 @@
+package httprepeater
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// StatusError is the error of an attempt which failed with a retryable response
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	if e.Status == "" {
+		return fmt.Sprintf("unexpected response status %d", e.StatusCode)
+	}
+
+	return "unexpected response status " + e.Status
+}
+
+// ErrorCode returns the response status code, it is the code of audit records, see repeater.WithAuditWriter
+func (e *StatusError) ErrorCode() string {
+	return strconv.Itoa(e.StatusCode)
+}
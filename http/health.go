@@ -0,0 +1,27 @@
+package httprepeater
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/amidgo/repeater"
+)
+
+// HealthHandler serves the health snapshot as JSON for readiness and liveness probes,
+// the status is 200 when the snapshot is healthy and 503 otherwise,
+// e.g. HealthHandler(supervisor.Health) or HealthHandler(monitor.Health)
+func HealthHandler(health func() repeater.Health) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		h := health()
+
+		status := http.StatusOK
+		if !h.Healthy() {
+			status = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+
+		_ = json.NewEncoder(w).Encode(h)
+	})
+}
@@ -0,0 +1,48 @@
+package httprepeater_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	httprepeater "github.com/amidgo/repeater/http"
+)
+
+func Test_HealthHandler(t *testing.T) {
+	t.Parallel()
+
+	health := repeater.Health{State: repeater.HealthRunning, LastSuccess: time.Now()}
+
+	handler := httprepeater.HealthHandler(func() repeater.Health { return health })
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", http.NoBody))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("wrong status, expected %d, actual %d", http.StatusOK, rec.Code)
+	}
+
+	health = repeater.Health{State: repeater.HealthFailing, LastError: io.ErrUnexpectedEOF, ConsecutiveFailures: 2}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", http.NoBody))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("wrong status, expected %d, actual %d", http.StatusServiceUnavailable, rec.Code)
+	}
+
+	var body map[string]any
+
+	err := json.Unmarshal(rec.Body.Bytes(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if body["state"] != "failing" || body["last_error"] != io.ErrUnexpectedEOF.Error() || body["consecutive_failures"] != float64(2) {
+		t.Fatalf("unexpected body %v", body)
+	}
+}
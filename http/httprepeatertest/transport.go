@@ -0,0 +1,100 @@
+package httprepeatertest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// ErrNoReplies is returned by Transport called more times than there are replies
+var ErrNoReplies = errors.New("httprepeatertest: no replies left")
+
+// Reply is a scripted round trip result, Err is returned instead of a response when not nil
+type Reply struct {
+	StatusCode int
+	Header     http.Header
+	Body       string
+	Err        error
+}
+
+// Transport is an http.RoundTripper replaying replies in order,
+// it fails the test on cleanup if some replies were not consumed
+type Transport struct {
+	t testing.TB
+
+	mu       sync.Mutex
+	replies  []Reply
+	requests []*http.Request
+}
+
+func NewTransport(t testing.TB, replies ...Reply) *Transport {
+	tr := &Transport{
+		t:       t,
+		replies: replies,
+	}
+
+	t.Cleanup(tr.assertConsumed)
+
+	return tr
+}
+
+// Client returns http.Client using the transport
+func (tr *Transport) Client() *http.Client {
+	return &http.Client{Transport: tr}
+}
+
+func (tr *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tr.requests = append(tr.requests, req)
+
+	if len(tr.requests) > len(tr.replies) {
+		tr.t.Errorf("unexpected request %d: %s %s", len(tr.requests), req.Method, req.URL)
+
+		return nil, ErrNoReplies
+	}
+
+	reply := tr.replies[len(tr.requests)-1]
+	if reply.Err != nil {
+		return nil, reply.Err
+	}
+
+	header := reply.Header
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", reply.StatusCode, http.StatusText(reply.StatusCode)),
+		StatusCode:    reply.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(reply.Body)),
+		ContentLength: int64(len(reply.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Requests returns a copy of received requests
+func (tr *Transport) Requests() []*http.Request {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	return append([]*http.Request(nil), tr.requests...)
+}
+
+func (tr *Transport) assertConsumed() {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if len(tr.requests) < len(tr.replies) {
+		tr.t.Errorf("%d of %d replies were not consumed", len(tr.replies)-len(tr.requests), len(tr.replies))
+	}
+}
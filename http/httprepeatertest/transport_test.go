@@ -0,0 +1,85 @@
+package httprepeatertest_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/amidgo/repeater"
+	httprepeater "github.com/amidgo/repeater/http"
+	"github.com/amidgo/repeater/http/httprepeatertest"
+)
+
+func Test_Transport(t *testing.T) {
+	t.Parallel()
+
+	tr := httprepeatertest.NewTransport(t,
+		httprepeatertest.Reply{Err: io.ErrUnexpectedEOF},
+		httprepeatertest.Reply{StatusCode: http.StatusServiceUnavailable},
+		httprepeatertest.Reply{StatusCode: http.StatusOK, Body: "ok"},
+	)
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := httprepeater.Do(repeater.New(repeater.ConstantProgression(0)), tr.Client(), req, 3)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" {
+		t.Fatalf("unexpected response %d %q", resp.StatusCode, body)
+	}
+
+	if len(tr.Requests()) != 3 {
+		t.Fatalf("expected 3 requests, actual %d", len(tr.Requests()))
+	}
+}
+
+type errorfRecorder struct {
+	testing.TB
+	errors  int
+	cleanup func()
+}
+
+func (e *errorfRecorder) Errorf(string, ...any) {
+	e.errors++
+}
+
+func (e *errorfRecorder) Cleanup(f func()) {
+	e.cleanup = f
+}
+
+func Test_Transport_NotConsumed(t *testing.T) {
+	t.Parallel()
+
+	tb := &errorfRecorder{TB: t}
+
+	tr := httprepeatertest.NewTransport(tb,
+		httprepeatertest.Reply{StatusCode: http.StatusOK},
+		httprepeatertest.Reply{StatusCode: http.StatusOK},
+	)
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = tr.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tb.cleanup()
+
+	if tb.errors != 1 {
+		t.Fatalf("expected 1 reported error, actual %d", tb.errors)
+	}
+}
@@ -0,0 +1,79 @@
+package httprepeater
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/amidgo/repeater"
+)
+
+// Transport is an http.RoundTripper sending requests with Repeater.Do, so clients which accept
+// a transport, e.g. resty.Client.SetTransport, retry without own retry settings.
+// A policy from the request context, see Middleware and ContextWithPolicy, replaces the default one
+type Transport struct {
+	base       http.RoundTripper
+	repeater   *Repeater
+	retryCount uint64
+}
+
+// NewTransport returns Transport sending attempts with base, http.DefaultTransport is used if base is nil
+func NewTransport(base http.RoundTripper, rp *Repeater, retryCount uint64) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &Transport{
+		base:       base,
+		repeater:   rp,
+		retryCount: retryCount,
+	}
+}
+
+// RoundTrip sends req, redirects are returned to the calling client unfollowed.
+// The body of req is closed on every path, even if no attempt was sent
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	client := &http.Client{
+		Transport: t.base,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	rp, retryCount := t.repeater, t.retryCount
+
+	policy, ok := PolicyFromContext(req.Context())
+	if ok {
+		scoped := *t.repeater
+		scoped.repeater = policy.Repeater()
+
+		rp, retryCount = &scoped, policy.RetryCount()
+	}
+
+	return rp.Do(client, req, retryCount)
+}
+
+type policyKey struct{}
+
+// ContextWithPolicy returns ctx carrying policy for requests sent by Transport
+func ContextWithPolicy(ctx context.Context, policy repeater.Policy) context.Context {
+	return context.WithValue(ctx, policyKey{}, policy)
+}
+
+// PolicyFromContext returns the policy of ContextWithPolicy
+func PolicyFromContext(ctx context.Context) (policy repeater.Policy, ok bool) {
+	policy, ok = ctx.Value(policyKey{}).(repeater.Policy)
+
+	return policy, ok
+}
+
+// Middleware is a server middleware, e.g. for chi.Router.Use, putting the policy of the incoming request
+// into its context, outbound requests made by the handler with that context and Transport use it
+func Middleware(policy func(r *http.Request) repeater.Policy) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := ContextWithPolicy(r.Context(), policy(r))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package httprepeater_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/amidgo/repeater"
+	httprepeater "github.com/amidgo/repeater/http"
+)
+
+func Test_Transport(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/target", http.StatusFound)
+	})
+	mux.HandleFunc("/target", func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	rp := httprepeater.New(repeater.New(repeater.ConstantProgression(0)))
+
+	client := &http.Client{Transport: httprepeater.NewTransport(srv.Client().Transport, rp, 2)}
+
+	resp, err := client.Get(srv.URL + "/redirect")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls.Load() != 2 {
+		t.Fatalf("unexpected response %d after %d calls", resp.StatusCode, calls.Load())
+	}
+}
+
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+
+	return nil
+}
+
+func Test_Transport_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	rp := httprepeater.New(repeater.New(repeater.ConstantProgression(0)))
+
+	transport := httprepeater.NewTransport(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		t.Fatal("unexpected attempt after the context is done")
+
+		return nil, nil
+	}), rp, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	body := &closeTracker{Reader: strings.NewReader("payload")}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://localhost", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = transport.RoundTrip(req)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled, actual %v", err)
+	}
+
+	if !body.closed {
+		t.Fatal("request body is not closed")
+	}
+}
+
+func Test_Middleware(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer upstream.Close()
+
+	rp := httprepeater.New(repeater.New(repeater.ConstantProgression(0)))
+
+	client := &http.Client{Transport: httprepeater.NewTransport(upstream.Client().Transport, rp, 3)}
+
+	handler := httprepeater.Middleware(func(r *http.Request) repeater.Policy {
+		return repeater.NewPolicy(repeater.WithMaxRetries(1))
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, upstream.URL, http.NoBody)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Error(err)
+
+			return
+		}
+
+		resp.Body.Close()
+
+		w.WriteHeader(resp.StatusCode)
+	}))
+
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	if rec.Code != http.StatusServiceUnavailable || calls.Load() != 2 {
+		t.Fatalf("request scoped policy must be used, actual %d response after %d calls", rec.Code, calls.Load())
+	}
+}
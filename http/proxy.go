@@ -0,0 +1,66 @@
+package httprepeater
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/amidgo/repeater"
+)
+
+// ProxyFailureFunc reports whether a failed response was generated by a fronting proxy rather than the origin
+type ProxyFailureFunc func(resp *http.Response) bool
+
+// WithProxyFailures retries failed responses generated by a proxy with progression instead of the repeater one,
+// e.g. a short constant progression for 502 and 504 of a load balancer which lost an upstream connection,
+// while origin 5xx keep backing off longer
+func WithProxyFailures(isProxyFailure ProxyFailureFunc, progression repeater.DurationProgression) Option {
+	return func(r *Repeater) {
+		r.isProxyFailure = isProxyFailure
+		r.proxyProgression = progression
+	}
+}
+
+// ProxyServerHeader matches 502, 503 and 504 responses with Server or Via header containing any of names,
+// case insensitive, e.g. ProxyServerHeader("envoy", "nginx") matches "Server: envoy" and "Via: 1.1 nginx".
+// Empty names are ignored, they would match every response
+func ProxyServerHeader(names ...string) ProxyFailureFunc {
+	lowerNames := make([]string, 0, len(names))
+
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" {
+			lowerNames = append(lowerNames, name)
+		}
+	}
+
+	return func(resp *http.Response) bool {
+		switch resp.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		default:
+			return false
+		}
+
+		// every Via header may list many comma separated proxies
+		values := append([]string{resp.Header.Get("Server")}, resp.Header.Values("Via")...)
+
+		for _, value := range values {
+			value = strings.ToLower(value)
+
+			for _, name := range lowerNames {
+				if strings.Contains(value, name) {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+}
+
+func (r *Repeater) proxyFailureDelay(resp *http.Response, attempt uint64) (repeater.Delay, bool) {
+	if r.isProxyFailure == nil || !r.isProxyFailure(resp) {
+		return repeater.Delay{}, false
+	}
+
+	return repeater.After(r.proxyProgression.Duration(attempt)), true
+}
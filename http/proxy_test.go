@@ -0,0 +1,82 @@
+package httprepeater_test
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	httprepeater "github.com/amidgo/repeater/http"
+	"github.com/amidgo/repeater/http/httprepeatertest"
+	"github.com/amidgo/repeater/repeatertest"
+)
+
+func Test_ProxyServerHeader(t *testing.T) {
+	t.Parallel()
+
+	isProxyFailure := httprepeater.ProxyServerHeader("envoy")
+
+	for _, test := range []struct {
+		status   int
+		header   http.Header
+		expected bool
+	}{
+		{status: http.StatusBadGateway, header: http.Header{"Server": {"envoy"}}, expected: true},
+		{status: http.StatusGatewayTimeout, header: http.Header{"Server": {"Envoy/1.30"}}, expected: true},
+		{status: http.StatusBadGateway, header: http.Header{"Server": {"gunicorn"}, "Via": {"1.1 envoy"}}, expected: true},
+		{status: http.StatusBadGateway, header: http.Header{"Via": {"1.0 fred", "1.1 varnish, 1.1 Envoy"}}, expected: true},
+		{status: http.StatusBadGateway, header: http.Header{"Server": {"gunicorn"}, "Via": {"1.1 varnish"}}},
+		{status: http.StatusBadGateway},
+		{status: http.StatusInternalServerError, header: http.Header{"Server": {"envoy"}}},
+	} {
+		resp := &http.Response{StatusCode: test.status, Header: test.header}
+
+		if isProxyFailure(resp) != test.expected {
+			t.Fatalf("wrong proxy failure of %d %v, expected %t", test.status, test.header, test.expected)
+		}
+	}
+
+	resp := &http.Response{StatusCode: http.StatusBadGateway, Header: http.Header{"Server": {"gunicorn"}}}
+
+	if httprepeater.ProxyServerHeader("", " ")(resp) {
+		t.Fatal("empty names must not match every response")
+	}
+}
+
+func Test_Do_WithProxyFailures(t *testing.T) {
+	t.Parallel()
+
+	tr := httprepeatertest.NewTransport(t,
+		httprepeatertest.Reply{StatusCode: http.StatusBadGateway, Header: http.Header{"Server": {"envoy"}}},
+		httprepeatertest.Reply{StatusCode: http.StatusBadGateway, Header: http.Header{"Server": {"gunicorn"}}},
+		httprepeatertest.Reply{StatusCode: http.StatusOK},
+	)
+
+	recorder := &repeatertest.Recorder{}
+
+	rp := httprepeater.New(
+		repeater.New(repeater.ConstantProgression(time.Millisecond*20), repeater.WithRecorder(recorder)),
+		httprepeater.WithProxyFailures(httprepeater.ProxyServerHeader("envoy"), repeater.ConstantProgression(time.Millisecond)),
+	)
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := rp.Do(tr.Client(), req, 2)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected response %d", resp.StatusCode)
+	}
+
+	expectedDelays := []time.Duration{time.Millisecond, time.Millisecond * 20}
+
+	if !slices.Equal(expectedDelays, recorder.Delays()) {
+		t.Fatalf("wrong delays, expected %v, actual %v", expectedDelays, recorder.Delays())
+	}
+}
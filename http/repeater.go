@@ -1,20 +1,28 @@
 package httprepeater
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"io"
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
+	"time"
 
 	"github.com/amidgo/repeater"
 )
 
 func Do(rp *repeater.Repeater, client *http.Client, req *http.Request, retryCount uint64) (*http.Response, error) {
-	httpRp := New(rp)
+	httpRp := Repeater{repeater: rp}
 
 	return httpRp.Do(client, req, retryCount)
 }
 
+// respReadLimit is the max number of bytes drained from a retried response body
+const respReadLimit = 4096
+
 var (
 	// A regular expression to match the error returned by net/http when the
 	// configured number of redirects is exhausted. This error isn't typed
@@ -35,56 +43,322 @@ var (
 	// TLS certificate is not trusted. This error isn't typed
 	// specifically so we resort to matching on the error string.
 	notTrustedErrorRe = regexp.MustCompile(`certificate is not trusted`)
+
+	// A regular expression to match the error returned by net/http when an
+	// HTTP/2 stream carrying the response body was closed before the body
+	// was read. This error isn't exported so we resort to matching on the error string.
+	http2StreamClosedErrorRe = regexp.MustCompile(`http2: (stream|response body) closed`)
+
+	// A regular expression to match the errors returned by net/http when an
+	// HTTP/2 server sent GOAWAY or reset the stream with REFUSED_STREAM,
+	// the request was not processed. These errors are not exported so we
+	// resort to matching on the error string.
+	http2GoAwayErrorRe = regexp.MustCompile(`GOAWAY|REFUSED_STREAM`)
 )
 
+// ShouldFinishFunc decides whether the response or the error of an attempt finishes the repeat,
+// a non-nil finishErr replaces err of the attempt, so Do returns it if the repeat finishes or gives up on the attempt
+type ShouldFinishFunc func(ctx context.Context, resp *http.Response, err error) (finished bool, finishErr error)
+
 type Repeater struct {
-	repeater *repeater.Repeater
+	repeater      *repeater.Repeater
+	shouldFinish  ShouldFinishFunc
+	bufferBody    bool
+	lastResponse  bool
+	spill         *bodySpill
+	honorEnvoy    bool
+	envoyHeader   http.Header
+	retryAfter    []RetryAfterFunc
+	maxRetryAfter time.Duration
+	cache         Cache
+	maxCachedBody int64
+
+	isProxyFailure   ProxyFailureFunc
+	proxyProgression repeater.DurationProgression
+}
+
+type Option func(r *Repeater)
+
+// WithShouldFinish replaces the default classification of responses and errors
+func WithShouldFinish(shouldFinish ShouldFinishFunc) Option {
+	return func(r *Repeater) {
+		r.shouldFinish = shouldFinish
+	}
+}
+
+// WithBodyVerification reads the whole body of a finishing response into memory before Do returns,
+// truncated transfers, e.g. io.ErrUnexpectedEOF or closed HTTP/2 streams, are retried when the request is replayable.
+// Bodies are not limited in size, so use it for responses which fit in memory
+func WithBodyVerification() Option {
+	return func(r *Repeater) {
+		r.bufferBody = true
+	}
+}
+
+// WithLastResponse makes Do return *repeater.Error together with the last response
+// when the repeat gave up on a retryable response, e.g. 503, the error wraps *StatusError.
+// By default such a response is returned with nil error. The body of the last response is not read,
+// so the caller inspects it for diagnostics and must close it
+func WithLastResponse() Option {
+	return func(r *Repeater) {
+		r.lastResponse = true
+	}
 }
 
-func New(rp *repeater.Repeater) *Repeater {
-	return &Repeater{
+func New(rp *repeater.Repeater, opts ...Option) *Repeater {
+	r := &Repeater{
 		repeater: rp,
 	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
 }
 
+// Do sends req with client until a response or an error is not retryable,
+// transport errors of a repeat which gave up are wrapped in *repeater.Error.
+// Every attempt sends a clone of req with the attempt number in its context, see AttemptFromContext.
+// Every attempt goes through client.Do, so the cookie jar and the redirect policy
+// of the client apply to each attempt separately. The body of a failed response is closed before the next attempt,
+// requests with a body are retried only if req.GetBody is set, e.g. by http.NewRequest, or WithBodySpill is used.
+// HTTP/2 GOAWAY and REFUSED_STREAM errors are retried immediately, without the progression delay,
+// retries of rate limited responses wait the delay requested by the server, see WithRetryAfter
 func (r *Repeater) Do(client *http.Client, req *http.Request, retryCount uint64) (resp *http.Response, err error) {
-	_ = r.repeater.RepeatContext(
+	if r.spill != nil && !replayable(req) {
+		spilled, cleanup, spillErr := r.spill.spillBody(req)
+		if spillErr != nil {
+			return nil, spillErr
+		}
+
+		defer cleanup()
+
+		req = spilled
+	}
+
+	classify := r.classify
+
+	if r.honorEnvoy {
+		if envoyRetries, ok := envoyRetryCount(req); ok {
+			retryCount = min(envoyRetries, retryCount)
+		}
+
+		if policy, ok := parseEnvoyPolicy(req); ok {
+			classify = policy.classify
+		}
+	}
+
+	var (
+		attempts uint64
+		// retryable is set when the last attempt failed with a retryable response or error
+		retryable bool
+	)
+
+	repeatErr := r.repeater.RepeatDelayErr(
 		req.Context(),
-		func(ctx context.Context) (finished bool) {
-			resp, err = client.Do(req)
+		func(ctx context.Context) (finished bool, delay repeater.Delay) {
+			if attempts > 0 {
+				discardResponse(resp)
+			}
+
+			attemptReq, reqErr := attemptRequest(ctx, req, attempts)
+			if reqErr != nil {
+				resp, err = nil, reqErr
+
+				return true, delay
+			}
 
-			return shouldFinishRetry(resp, err)
+			for key, values := range r.envoyHeader {
+				attemptReq.Header[key] = slices.Clone(values)
+			}
+
+			attempts++
+
+			resp, err = client.Do(attemptReq)
+
+			finished, err = classify(ctx, resp, err)
+			if finished && r.bufferBody && err == nil {
+				resp, err = bufferBody(resp)
+				if err != nil {
+					finished = !isTruncationError(err)
+				}
+			}
+
+			if finished && err == nil && r.cache != nil && cacheable(req) && resp.StatusCode == http.StatusOK {
+				resp, err = storeResponse(r.cache, r.maxCachedBodySize(), req, resp)
+				if err != nil {
+					finished = !isTruncationError(err)
+				}
+			}
+
+			retryable = !finished
+
+			// The connection was shut down gracefully, a new one almost always succeeds.
+			if err != nil && isConnectionRefusedGracefully(err) {
+				delay = repeater.After(0)
+			}
+
+			if !finished && resp != nil {
+				proxyDelay, ok := r.proxyFailureDelay(resp, attempts-1)
+				if ok {
+					delay = proxyDelay
+				}
+
+				retryAfter, ok := r.retryAfterDelay(resp)
+				if ok {
+					delay = repeater.After(retryAfter)
+				}
+			}
+
+			return finished || !replayable(req), delay
 		},
 		retryCount,
 	)
 
+	finished := repeatErr == nil
+
+	// the request context was done before the first attempt, the body is closed like client.Do does
+	if resp == nil && err == nil {
+		err = req.Context().Err()
+
+		if attempts == 0 && req.Body != nil {
+			_ = req.Body.Close()
+		}
+	}
+
+	if retryable && r.cache != nil && cacheable(req) && req.Context().Err() == nil {
+		stale, ok := staleResponse(r.cache, req)
+		if ok {
+			discardResponse(resp)
+
+			return stale, nil
+		}
+	}
+
+	if !finished && err == nil && resp != nil && r.lastResponse {
+		err = &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	if !finished && err != nil {
+		gaveUp := repeatErr.(*repeater.Error)
+		gaveUp.Last = err
+		err = gaveUp
+	}
+
 	return resp, err
 }
 
+func (r *Repeater) classify(ctx context.Context, resp *http.Response, err error) (finished bool, _ error) {
+	if r.shouldFinish == nil {
+		return shouldFinishRetry(resp, err), err
+	}
+
+	finished, finishErr := r.shouldFinish(ctx, resp, err)
+	if finishErr != nil {
+		return finished, finishErr
+	}
+
+	return finished, err
+}
+
+// bufferBody reads the whole body of resp into memory, resp is nil if reading failed
+func bufferBody(resp *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return resp, nil
+}
+
+// isTruncationError reports whether a response body read failed because the transfer was cut
+func isTruncationError(err error) bool {
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, http.ErrBodyReadAfterClose) ||
+		http2StreamClosedErrorRe.MatchString(err.Error())
+}
+
+// isConnectionRefusedGracefully reports whether the server sent HTTP/2 GOAWAY or refused the stream
+func isConnectionRefusedGracefully(err error) bool {
+	return http2GoAwayErrorRe.MatchString(err.Error())
+}
+
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+type attemptKey struct{}
+
+// AttemptFromContext returns the attempt number of a request sent by Do,
+// zero is the initial attempt, ok is false for requests sent not by Do
+func AttemptFromContext(ctx context.Context) (attempt uint64, ok bool) {
+	attempt, ok = ctx.Value(attemptKey{}).(uint64)
+
+	return attempt, ok
+}
+
+// attemptRequest clones req for every attempt, so lower transports mutating headers
+// don't affect next attempts, retried attempts get a new body from req.GetBody
+func attemptRequest(ctx context.Context, req *http.Request, attempt uint64) (*http.Request, error) {
+	attemptReq := req.Clone(context.WithValue(ctx, attemptKey{}, attempt))
+
+	if attempt == 0 || req.Body == nil || req.Body == http.NoBody {
+		return attemptReq, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	attemptReq.Body = body
+
+	return attemptReq, nil
+}
+
+// discardResponse drains and closes the body of a retried response, so the connection may be reused
+func discardResponse(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, respReadLimit))
+	_ = resp.Body.Close()
+}
+
 func shouldFinishRetry(resp *http.Response, err error) bool {
 	if err != nil {
 		if v, ok := err.(*url.Error); ok {
+			// Typed errors are cheaper to check than the error message.
+			if isCertError(v.Err) {
+				return true
+			}
+
+			// The error message is formatted on every call, so build it once for all checks.
+			msg := v.Error()
+
 			// Don't retry if the error was due to too many redirects.
-			if redirectsErrorRe.MatchString(v.Error()) {
+			if redirectsErrorRe.MatchString(msg) {
 				return true
 			}
 
 			// Don't retry if the error was due to an invalid protocol scheme.
-			if schemeErrorRe.MatchString(v.Error()) {
+			if schemeErrorRe.MatchString(msg) {
 				return true
 			}
 
 			// Don't retry if the error was due to an invalid header.
-			if invalidHeaderErrorRe.MatchString(v.Error()) {
+			if invalidHeaderErrorRe.MatchString(msg) {
 				return true
 			}
 
 			// Don't retry if the error was due to TLS cert verification failure.
-			if notTrustedErrorRe.MatchString(v.Error()) {
-				return true
-			}
-
-			if isCertError(v.Err) {
+			if notTrustedErrorRe.MatchString(msg) {
 				return true
 			}
 		}
@@ -93,6 +367,12 @@ func shouldFinishRetry(resp *http.Response, err error) bool {
 		return false
 	}
 
+	// 403 Forbidden with an exhausted rate limit is recoverable after
+	// the rate limit reset.
+	if rateLimited(resp) {
+		return false
+	}
+
 	// 429 Too Many Requests is recoverable. Sometimes the server puts
 	// a Retry-After response header to indicate when the server is
 	// available to start processing request from client.
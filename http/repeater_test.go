@@ -1 +1,204 @@
 package httprepeater_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	httprepeater "github.com/amidgo/repeater/http"
+	"github.com/amidgo/repeater/http/httprepeatertest"
+)
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func Test_Do_ErrorExceeded(t *testing.T) {
+	t.Parallel()
+
+	errConnReset := errors.New("connection reset")
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errConnReset
+		}),
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = httprepeater.Do(repeater.New(repeater.ConstantProgression(0)), client, req, 2)
+
+	repeatErr := &repeater.Error{}
+	if !errors.As(err, &repeatErr) {
+		t.Fatalf("expected *repeater.Error, actual %v", err)
+	}
+
+	if repeatErr.Attempts != 3 || !repeatErr.Exceeded {
+		t.Fatalf("unexpected error %+v", repeatErr)
+	}
+
+	if !errors.Is(err, errConnReset) {
+		t.Fatalf("expected error wraps last attempt error, actual %v", err)
+	}
+
+	if !errors.Is(err, repeater.ErrRetryCountExceeded) {
+		t.Fatalf("expected error wraps repeater.ErrRetryCountExceeded, actual %v", err)
+	}
+
+	retryCountErr := &repeater.RetryCountError{}
+	if !errors.As(err, &retryCountErr) || retryCountErr.Attempts != 3 || retryCountErr.MaxAttempts != 3 {
+		t.Fatalf("expected *repeater.RetryCountError with 3/3 attempts, actual %v", err)
+	}
+}
+
+func Test_Do_ContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			t.Fatal("unexpected request")
+
+			return nil, nil
+		}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = httprepeater.Do(repeater.New(repeater.ConstantProgression(0)), client, req, 2)
+
+	repeatErr := &repeater.Error{}
+	if !errors.As(err, &repeatErr) {
+		t.Fatalf("expected *repeater.Error, actual %v", err)
+	}
+
+	if repeatErr.Attempts != 0 || repeatErr.Exceeded {
+		t.Fatalf("unexpected error %+v", repeatErr)
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, actual %v", err)
+	}
+}
+
+func Test_Do_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	errConnReset := errors.New("connection reset")
+
+	t.Run("canceled during sleep", func(t *testing.T) {
+		t.Parallel()
+
+		client := &http.Client{
+			Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+				return nil, errConnReset
+			}),
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(time.Millisecond*10, cancel)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost", http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = httprepeater.Do(repeater.New(repeater.ConstantProgression(time.Hour)), client, req, 2)
+
+		repeatErr := &repeater.Error{}
+		if !errors.As(err, &repeatErr) {
+			t.Fatalf("expected *repeater.Error, actual %v", err)
+		}
+
+		if repeatErr.Attempts != 1 || repeatErr.Exceeded || !repeatErr.InSleep || !repeatErr.Canceled() {
+			t.Fatalf("unexpected error %+v", repeatErr)
+		}
+
+		if !errors.Is(err, errConnReset) {
+			t.Fatalf("expected error wraps last attempt error, actual %v", err)
+		}
+	})
+
+	t.Run("deadline during attempt", func(t *testing.T) {
+		t.Parallel()
+
+		client := &http.Client{
+			Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				<-req.Context().Done()
+
+				return nil, req.Context().Err()
+			}),
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost", http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = httprepeater.Do(repeater.New(repeater.ConstantProgression(0)), client, req, 2)
+
+		repeatErr := &repeater.Error{}
+		if !errors.As(err, &repeatErr) {
+			t.Fatalf("expected *repeater.Error, actual %v", err)
+		}
+
+		if repeatErr.Attempts != 1 || repeatErr.Exceeded || repeatErr.InSleep || !repeatErr.DeadlineExceeded() {
+			t.Fatalf("unexpected error %+v", repeatErr)
+		}
+	})
+}
+
+func Test_Do_WithLastResponse(t *testing.T) {
+	t.Parallel()
+
+	tr := httprepeatertest.NewTransport(t,
+		httprepeatertest.Reply{StatusCode: http.StatusServiceUnavailable},
+		httprepeatertest.Reply{StatusCode: http.StatusServiceUnavailable, Body: "maintenance"},
+	)
+
+	rp := httprepeater.New(repeater.New(repeater.ConstantProgression(0)), httprepeater.WithLastResponse())
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := rp.Do(tr.Client(), req, 1)
+	if resp == nil {
+		t.Fatalf("expected last response, actual nil")
+	}
+
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "maintenance" {
+		t.Fatalf("wrong last response body, expected %q, actual %q", "maintenance", body)
+	}
+
+	repeatErr := &repeater.Error{}
+	if !errors.As(err, &repeatErr) || !repeatErr.Exceeded || repeatErr.Attempts != 2 {
+		t.Fatalf("expected exceeded *repeater.Error, actual %v", err)
+	}
+
+	statusErr := &httprepeater.StatusError{}
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected *httprepeater.StatusError, actual %v", err)
+	}
+}
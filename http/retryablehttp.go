@@ -0,0 +1,239 @@
+package httprepeater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+// CheckRetry mirrors github.com/hashicorp/go-retryablehttp.CheckRetry,
+// so existing policies can be passed without importing the module
+type CheckRetry func(ctx context.Context, resp *http.Response, err error) (bool, error)
+
+// Backoff mirrors github.com/hashicorp/go-retryablehttp.Backoff
+type Backoff func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration
+
+// FromRetryableHTTP converts go-retryablehttp CheckRetry and Backoff into a ShouldFinishFunc for WithShouldFinish
+// and a progression for repeater.New, min and max are the RetryWaitMin and RetryWaitMax client settings.
+// The repeat finishes only if checkRetry returns false, its error is the error of the attempt either way.
+// The progression doesn't see responses, so backoff is called with nil response and Retry-After headers are ignored
+func FromRetryableHTTP(checkRetry CheckRetry, backoff Backoff, min, max time.Duration) (ShouldFinishFunc, BackoffProgression) {
+	shouldFinish := func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		retry, checkErr := checkRetry(ctx, resp, err)
+
+		return !retry, checkErr
+	}
+
+	return shouldFinish, BackoffProgression{backoff: backoff, min: min, max: max}
+}
+
+// BackoffProgression is a repeater.DurationProgression calling go-retryablehttp Backoff
+type BackoffProgression struct {
+	backoff  Backoff
+	min, max time.Duration
+}
+
+func (b BackoffProgression) Duration(attempt uint64) time.Duration {
+	return b.backoff(b.min, b.max, int(attempt), nil)
+}
+
+// Logger mirrors github.com/hashicorp/go-retryablehttp.Logger
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+// LeveledLogger mirrors github.com/hashicorp/go-retryablehttp.LeveledLogger
+type LeveledLogger interface {
+	Error(msg string, keysAndValues ...any)
+	Info(msg string, keysAndValues ...any)
+	Debug(msg string, keysAndValues ...any)
+	Warn(msg string, keysAndValues ...any)
+}
+
+// DefaultBackoff mirrors go-retryablehttp.DefaultBackoff, the delay is min*2^attemptNum capped at max.
+// Retry-After headers are handled by Do, so resp is ignored
+func DefaultBackoff(min, max time.Duration, attemptNum int, _ *http.Response) time.Duration {
+	mult := math.Pow(2, float64(attemptNum)) * float64(min)
+
+	sleep := time.Duration(mult)
+	if float64(sleep) != mult || sleep > max {
+		sleep = max
+	}
+
+	return sleep
+}
+
+// Client mirrors the surface of go-retryablehttp.Client, so code using it may switch with an import change.
+// Requests are sent by Repeater.Do with default Retry-After handling. Unlike go-retryablehttp,
+// Do accepts *http.Request and retries requests with a body only if req.GetBody is set.
+// When the retries are exhausted on a retryable response, its body is closed
+// and Do returns nil response with *repeater.Error wrapping *StatusError or the CheckRetry error
+type Client struct {
+	// HTTPClient sends the requests, http.DefaultClient is used if nil
+	HTTPClient *http.Client
+	// Logger is either Logger or LeveledLogger, retries and given up requests are logged if set
+	Logger any
+
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+	RetryMax     int
+
+	// CheckRetry replaces the default classification of responses and errors if set
+	CheckRetry CheckRetry
+	// Backoff is DefaultBackoff if nil
+	Backoff Backoff
+}
+
+// NewClient returns Client with go-retryablehttp.NewClient defaults
+func NewClient() *Client {
+	return &Client{
+		HTTPClient:   http.DefaultClient,
+		RetryWaitMin: time.Second,
+		RetryWaitMax: time.Second * 30,
+		RetryMax:     4,
+		Backoff:      DefaultBackoff,
+	}
+}
+
+// Do sends req retrying it at most RetryMax times
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	backoff := c.Backoff
+	if backoff == nil {
+		backoff = DefaultBackoff
+	}
+
+	opts := []Option{WithLastResponse()}
+
+	if c.CheckRetry != nil {
+		shouldFinish, _ := FromRetryableHTTP(c.CheckRetry, backoff, c.RetryWaitMin, c.RetryWaitMax)
+
+		opts = append(opts, WithShouldFinish(shouldFinish))
+	}
+
+	var rpOpts []repeater.Option
+
+	if c.Logger != nil {
+		recorder, err := newLoggerRecorder(req, c.Logger)
+		if err != nil {
+			return nil, err
+		}
+
+		rpOpts = append(rpOpts, repeater.WithRecorder(recorder))
+	}
+
+	progression := BackoffProgression{backoff: backoff, min: c.RetryWaitMin, max: c.RetryWaitMax}
+
+	rp := New(repeater.New(progression, rpOpts...), opts...)
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := rp.Do(httpClient, req, uint64(max(c.RetryMax, 0)))
+
+	var repeatErr *repeater.Error
+	if errors.As(err, &repeatErr) {
+		discardResponse(resp)
+
+		return nil, err
+	}
+
+	return resp, err
+}
+
+// Get sends GET request to url
+func (c *Client) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(req)
+}
+
+// Head sends HEAD request to url
+func (c *Client) Head(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodHead, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(req)
+}
+
+// Post sends POST request to url, the request is retried only if body is
+// *bytes.Buffer, *bytes.Reader or *strings.Reader, see http.NewRequest
+func (c *Client) Post(url, bodyType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", bodyType)
+
+	return c.Do(req)
+}
+
+// PostForm sends POST request to url with url-encoded data
+func (c *Client) PostForm(url string, data url.Values) (*http.Response, error) {
+	return c.Post(url, "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
+}
+
+// loggerRecorder logs retries of a request in go-retryablehttp format
+type loggerRecorder struct {
+	method, url string
+	logger      Logger
+	leveled     LeveledLogger
+}
+
+func newLoggerRecorder(req *http.Request, logger any) (loggerRecorder, error) {
+	recorder := loggerRecorder{method: req.Method, url: req.URL.Redacted()}
+
+	switch logger := logger.(type) {
+	case Logger:
+		recorder.logger = logger
+	case LeveledLogger:
+		recorder.leveled = logger
+	default:
+		return loggerRecorder{}, fmt.Errorf("invalid logger type %T, must be Logger or LeveledLogger", logger)
+	}
+
+	return recorder, nil
+}
+
+func (l loggerRecorder) AttemptStarted(context.Context, uint64, time.Duration) {}
+
+func (l loggerRecorder) AttemptFinished(context.Context, uint64, time.Duration, bool) {}
+
+func (l loggerRecorder) RetryScheduled(_ context.Context, attempt uint64, _, delay time.Duration) {
+	if l.leveled != nil {
+		l.leveled.Debug("retrying request", "request", l.method+" "+l.url, "wait", delay, "attempt", attempt)
+
+		return
+	}
+
+	l.logger.Printf("[DEBUG] %s %s: retrying in %s (attempt %d)", l.method, l.url, delay, attempt)
+}
+
+func (l loggerRecorder) GaveUp(_ context.Context, attempt uint64, _ time.Duration, exhausted bool) {
+	if !exhausted {
+		return
+	}
+
+	if l.leveled != nil {
+		l.leveled.Error("giving up", "request", l.method+" "+l.url, "attempts", attempt+1)
+
+		return
+	}
+
+	l.logger.Printf("[ERR] %s %s giving up after %d attempt(s)", l.method, l.url, attempt+1)
+}
@@ -0,0 +1,260 @@
+package httprepeater_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	httprepeater "github.com/amidgo/repeater/http"
+)
+
+func Test_FromRetryableHTTP(t *testing.T) {
+	t.Parallel()
+
+	errPolicy := errors.New("policy error")
+
+	statuses := []int{http.StatusBadGateway, http.StatusConflict, http.StatusTeapot}
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			status := statuses[0]
+			statuses = statuses[1:]
+
+			return &http.Response{StatusCode: status, Body: http.NoBody}, nil
+		}),
+	}
+
+	checkRetry := func(_ context.Context, resp *http.Response, _ error) (bool, error) {
+		switch resp.StatusCode {
+		case http.StatusBadGateway, http.StatusConflict:
+			return true, nil
+		default:
+			return false, errPolicy
+		}
+	}
+
+	var attempts []int
+
+	backoff := func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+		if min != time.Millisecond || max != time.Second || resp != nil {
+			t.Errorf("unexpected backoff args: %s %s %v", min, max, resp)
+		}
+
+		attempts = append(attempts, attemptNum)
+
+		return 0
+	}
+
+	shouldFinish, progression := httprepeater.FromRetryableHTTP(checkRetry, backoff, time.Millisecond, time.Second)
+
+	rp := httprepeater.New(repeater.New(progression), httprepeater.WithShouldFinish(shouldFinish))
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := rp.Do(client, req, 5)
+	if !errors.Is(err, errPolicy) {
+		t.Fatalf("expected policy error, actual %v", err)
+	}
+
+	if resp.StatusCode != http.StatusTeapot {
+		t.Fatalf("unexpected last response status %d", resp.StatusCode)
+	}
+
+	if len(attempts) != 2 || attempts[0] != 0 || attempts[1] != 1 {
+		t.Fatalf("unexpected backoff attempts %v", attempts)
+	}
+}
+
+func Test_FromRetryableHTTP_RetryWithError(t *testing.T) {
+	t.Parallel()
+
+	errUnexpectedStatus := errors.New("unexpected HTTP status")
+
+	// checkRetry returns an error with retry like go-retryablehttp.DefaultRetryPolicy for 5xx responses
+	checkRetry := func(_ context.Context, resp *http.Response, _ error) (bool, error) {
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return true, fmt.Errorf("%w %s", errUnexpectedStatus, resp.Status)
+		}
+
+		return false, nil
+	}
+
+	backoff := func(time.Duration, time.Duration, int, *http.Response) time.Duration { return 0 }
+
+	shouldFinish, progression := httprepeater.FromRetryableHTTP(checkRetry, backoff, 0, 0)
+
+	rp := httprepeater.New(repeater.New(progression), httprepeater.WithShouldFinish(shouldFinish))
+
+	newClient := func(statuses ...int) *http.Client {
+		return &http.Client{
+			Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+				status := statuses[0]
+				statuses = statuses[1:]
+
+				return &http.Response{StatusCode: status, Status: http.StatusText(status), Body: http.NoBody}, nil
+			}),
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := rp.Do(newClient(http.StatusServiceUnavailable, http.StatusInternalServerError, http.StatusOK), req, 2)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("wrong status, expected %d, actual %d", http.StatusOK, resp.StatusCode)
+	}
+
+	_, err = rp.Do(newClient(http.StatusServiceUnavailable, http.StatusServiceUnavailable), req, 1)
+	if !errors.Is(err, errUnexpectedStatus) || !errors.Is(err, repeater.ErrRetryCountExceeded) {
+		t.Fatalf("expected exhausted retries with the check error, actual %v", err)
+	}
+
+	client := httprepeater.NewClient()
+	client.HTTPClient = newClient(http.StatusBadGateway, http.StatusOK)
+	client.RetryWaitMin = 0
+	client.RetryWaitMax = 0
+	client.CheckRetry = checkRetry
+
+	resp, err = client.Get("http://localhost")
+	if err != nil {
+		t.Fatalf("unexpected client error %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("wrong client status, expected %d, actual %d", http.StatusOK, resp.StatusCode)
+	}
+}
+
+type printfLogger struct {
+	lines []string
+}
+
+func (p *printfLogger) Printf(format string, args ...any) {
+	p.lines = append(p.lines, fmt.Sprintf(format, args...))
+}
+
+func Test_Client(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("unexpected body %q", body)
+		}
+
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	logger := &printfLogger{}
+
+	client := httprepeater.NewClient()
+	client.HTTPClient = srv.Client()
+	client.RetryWaitMin = time.Millisecond
+	client.RetryWaitMax = time.Millisecond * 2
+	client.Logger = logger
+
+	resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Fatalf("unexpected response %d after %d calls", resp.StatusCode, calls.Load())
+	}
+
+	if len(logger.lines) != 2 || !strings.Contains(logger.lines[1], "retrying in 2ms (attempt 2)") {
+		t.Fatalf("unexpected log %q", logger.lines)
+	}
+
+	calls.Store(0)
+	client.RetryMax = 1
+
+	resp, err = client.Post(srv.URL, "text/plain", strings.NewReader("payload"))
+	if resp != nil {
+		t.Fatalf("unexpected response %d", resp.StatusCode)
+	}
+
+	var statusErr *httprepeater.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected status error, actual %v", err)
+	}
+
+	if !strings.Contains(logger.lines[len(logger.lines)-1], "giving up after 2 attempt(s)") {
+		t.Fatalf("unexpected log %q", logger.lines)
+	}
+
+	client.Logger = struct{}{}
+
+	_, err = client.Get(srv.URL)
+	if err == nil {
+		t.Fatal("expected invalid logger error")
+	}
+}
+
+func Test_Client_CheckRetry(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+
+	client := &httprepeater.Client{
+		HTTPClient: &http.Client{
+			Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+				calls++
+
+				return &http.Response{StatusCode: http.StatusConflict, Body: http.NoBody}, nil
+			}),
+		},
+		RetryMax: 2,
+		CheckRetry: func(_ context.Context, resp *http.Response, _ error) (bool, error) {
+			return resp.StatusCode == http.StatusConflict, nil
+		},
+		Backoff: func(time.Duration, time.Duration, int, *http.Response) time.Duration {
+			return 0
+		},
+	}
+
+	_, err := client.Get("http://localhost")
+
+	var repeatErr *repeater.Error
+	if !errors.As(err, &repeatErr) || repeatErr.Attempts != 3 || calls != 3 {
+		t.Fatalf("expected exhausted repeat after 3 calls, actual %v after %d calls", err, calls)
+	}
+}
+
+func Test_DefaultBackoff(t *testing.T) {
+	t.Parallel()
+
+	for attempt, expected := range []time.Duration{time.Second, time.Second * 2, time.Second * 4, time.Second * 5} {
+		delay := httprepeater.DefaultBackoff(time.Second, time.Second*5, attempt, nil)
+		if delay != expected {
+			t.Fatalf("wrong delay of attempt %d, expected %s, actual %s", attempt, expected, delay)
+		}
+	}
+
+	if delay := httprepeater.DefaultBackoff(time.Second, time.Second*5, 100, nil); delay != time.Second*5 {
+		t.Fatalf("wrong overflowed delay %s", delay)
+	}
+}
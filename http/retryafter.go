@@ -0,0 +1,132 @@
+package httprepeater
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryAfterFunc returns the delay before retrying resp requested by the server,
+// ok is false if resp doesn't carry one
+type RetryAfterFunc func(resp *http.Response, now time.Time) (delay time.Duration, ok bool)
+
+// WithRetryAfter replaces header parsers used to delay retries of failed responses, the first parsed delay wins.
+// RetryAfterHeader and RateLimitResetHeader are used by default, pass no parsers to use only the progression
+func WithRetryAfter(parsers ...RetryAfterFunc) Option {
+	return func(r *Repeater) {
+		// non-nil slice disables default parsers when no parsers passed
+		r.retryAfter = append([]RetryAfterFunc{}, parsers...)
+	}
+}
+
+var defaultRetryAfter = []RetryAfterFunc{RetryAfterHeader, RateLimitResetHeader}
+
+// DefaultMaxRetryAfter is the max delay requested by a server which Do waits unless WithMaxRetryAfter is used
+const DefaultMaxRetryAfter = time.Minute * 5
+
+// WithMaxRetryAfter limits delays requested by servers, see WithRetryAfter, longer delays are clamped to limit,
+// so a misbehaving server can't stall Do for hours. DefaultMaxRetryAfter is used by default
+func WithMaxRetryAfter(limit time.Duration) Option {
+	return func(r *Repeater) {
+		r.maxRetryAfter = limit
+	}
+}
+
+// RetryAfterHeader parses Retry-After header of 429 and 503 responses in seconds or HTTP-date form
+func RetryAfterHeader(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err == nil {
+		return secondsDuration(seconds), true
+	}
+
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(at.Sub(now), 0), true
+}
+
+// unixResetThreshold separates X-RateLimit-Reset unix timestamps from delays in seconds
+const unixResetThreshold = 1_000_000_000
+
+// RateLimitResetHeader parses RateLimit-Reset and X-RateLimit-Reset headers of 429 and 403 responses,
+// the value is either delay in seconds or unix timestamp in seconds, as GitHub sends it
+func RateLimitResetHeader(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusForbidden {
+		return 0, false
+	}
+
+	for _, key := range []string{"RateLimit-Reset", "X-RateLimit-Reset"} {
+		value := resp.Header.Get(key)
+		if value == "" {
+			continue
+		}
+
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if seconds >= unixResetThreshold {
+			return max(time.Unix(seconds, 0).Sub(now), 0), true
+		}
+
+		return secondsDuration(seconds), true
+	}
+
+	return 0, false
+}
+
+// secondsDuration converts seconds to a duration, negative seconds are zero and overflowed ones are the max duration
+func secondsDuration(seconds int64) time.Duration {
+	switch {
+	case seconds <= 0:
+		return 0
+	case seconds > math.MaxInt64/int64(time.Second):
+		return math.MaxInt64
+	default:
+		return time.Duration(seconds) * time.Second
+	}
+}
+
+// rateLimited reports whether a 403 response is caused by an exhausted rate limit
+func rateLimited(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0"
+}
+
+func (r *Repeater) retryAfterDelay(resp *http.Response) (time.Duration, bool) {
+	parsers := r.retryAfter
+	if parsers == nil {
+		parsers = defaultRetryAfter
+	}
+
+	now := time.Now()
+
+	for _, parse := range parsers {
+		delay, ok := parse(resp, now)
+		if ok {
+			return min(delay, r.maxRetryAfterDelay()), true
+		}
+	}
+
+	return 0, false
+}
+
+func (r *Repeater) maxRetryAfterDelay() time.Duration {
+	if r.maxRetryAfter > 0 {
+		return r.maxRetryAfter
+	}
+
+	return DefaultMaxRetryAfter
+}
@@ -0,0 +1,152 @@
+package httprepeater_test
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	httprepeater "github.com/amidgo/repeater/http"
+	"github.com/amidgo/repeater/http/httprepeatertest"
+	"github.com/amidgo/repeater/repeatertest"
+)
+
+func Test_RetryAfterHeader(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		status   int
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{status: http.StatusTooManyRequests, value: "3", expected: time.Second * 3, ok: true},
+		{status: http.StatusServiceUnavailable, value: now.Add(time.Minute).Format(http.TimeFormat), expected: time.Minute, ok: true},
+		{status: http.StatusTooManyRequests, value: "9223372036854775807", expected: math.MaxInt64, ok: true},
+		{status: http.StatusTooManyRequests, value: "-3", expected: 0, ok: true},
+		{status: http.StatusTooManyRequests, value: "soon"},
+		{status: http.StatusTooManyRequests},
+		{status: http.StatusInternalServerError, value: "3"},
+	} {
+		resp := &http.Response{StatusCode: test.status, Header: http.Header{}}
+		resp.Header.Set("Retry-After", test.value)
+
+		delay, ok := httprepeater.RetryAfterHeader(resp, now)
+		if delay != test.expected || ok != test.ok {
+			t.Fatalf("wrong Retry-After %q of %d, expected %s %t, actual %s %t", test.value, test.status, test.expected, test.ok, delay, ok)
+		}
+	}
+}
+
+func Test_RateLimitResetHeader(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		status   int
+		key      string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{status: http.StatusTooManyRequests, key: "RateLimit-Reset", value: "5", expected: time.Second * 5, ok: true},
+		{status: http.StatusForbidden, key: "X-RateLimit-Reset", value: strconv.FormatInt(now.Add(time.Minute).Unix(), 10), expected: time.Minute, ok: true},
+		{status: http.StatusForbidden, key: "X-RateLimit-Reset", value: "later"},
+		{status: http.StatusBadGateway, key: "RateLimit-Reset", value: "5"},
+	} {
+		resp := &http.Response{StatusCode: test.status, Header: http.Header{}}
+		resp.Header.Set(test.key, test.value)
+
+		delay, ok := httprepeater.RateLimitResetHeader(resp, now)
+		if delay != test.expected || ok != test.ok {
+			t.Fatalf("wrong %s %q of %d, expected %s %t, actual %s %t", test.key, test.value, test.status, test.expected, test.ok, delay, ok)
+		}
+	}
+}
+
+func Test_Do_RetryAfter(t *testing.T) {
+	t.Parallel()
+
+	tr := httprepeatertest.NewTransport(t,
+		httprepeatertest.Reply{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"0"}}},
+		httprepeatertest.Reply{
+			StatusCode: http.StatusForbidden,
+			Header:     http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"0"}},
+		},
+		httprepeatertest.Reply{StatusCode: http.StatusOK},
+	)
+
+	recorder := &repeatertest.Recorder{}
+
+	rp := repeater.New(repeater.ConstantProgression(time.Hour), repeater.WithRecorder(recorder))
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := httprepeater.Do(rp, tr.Client(), req, 2)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected response %d", resp.StatusCode)
+	}
+
+	for _, delay := range recorder.Delays() {
+		if delay != 0 {
+			t.Fatalf("expected server requested delays, actual %v", recorder.Delays())
+		}
+	}
+}
+
+func Test_Do_WithMaxRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		opts     []httprepeater.Option
+		expected time.Duration
+	}{
+		{name: "default", expected: httprepeater.DefaultMaxRetryAfter},
+		{name: "option", opts: []httprepeater.Option{httprepeater.WithMaxRetryAfter(time.Millisecond)}, expected: time.Millisecond},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			tr := httprepeatertest.NewTransport(t,
+				httprepeatertest.Reply{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"86400"}}},
+				httprepeatertest.Reply{StatusCode: http.StatusOK},
+			)
+
+			recorder := &repeatertest.Recorder{}
+
+			rp := httprepeater.New(
+				repeater.New(repeater.ConstantProgression(0), repeater.WithRecorder(recorder)),
+				test.opts...,
+			)
+
+			req, err := http.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := rp.Do(tr.Client(), req.WithContext(repeater.ContextWithoutSleep(context.Background())), 1)
+			if err != nil || resp.StatusCode != http.StatusOK {
+				t.Fatalf("unexpected response %v, error %v", resp, err)
+			}
+
+			delays := recorder.Delays()
+			if len(delays) != 1 || delays[0] != test.expected {
+				t.Fatalf("wrong delays, expected [%s], actual %v", test.expected, delays)
+			}
+		})
+	}
+}
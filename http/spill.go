@@ -0,0 +1,69 @@
+package httprepeater
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+)
+
+// WithBodySpill makes requests with a body which can't be replayed, i.e. req.GetBody is not set, retryable.
+// Do reads the body once before the first attempt, bodies up to memLimit bytes are kept in memory,
+// larger ones are written to a temporary file in dir, the default temporary directory if dir is empty.
+// The file is removed when Do returns
+func WithBodySpill(memLimit int64, dir string) Option {
+	return func(r *Repeater) {
+		r.spill = &bodySpill{memLimit: memLimit, dir: dir}
+	}
+}
+
+type bodySpill struct {
+	memLimit int64
+	dir      string
+}
+
+// spillBody returns a shallow copy of req with a replayable body, cleanup removes the temporary file
+func (s *bodySpill) spillBody(req *http.Request) (_ *http.Request, cleanup func(), err error) {
+	defer req.Body.Close()
+
+	head, err := io.ReadAll(io.LimitReader(req.Body, s.memLimit+1))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if int64(len(head)) <= s.memLimit {
+		return withBody(req, int64(len(head)), func() io.Reader { return bytes.NewReader(head) }), func() {}, nil
+	}
+
+	file, err := os.CreateTemp(s.dir, "httprepeater-body-*")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cleanup = func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}
+
+	size, err := io.Copy(file, io.MultiReader(bytes.NewReader(head), req.Body))
+	if err != nil {
+		cleanup()
+
+		return nil, nil, err
+	}
+
+	return withBody(req, size, func() io.Reader { return io.NewSectionReader(file, 0, size) }), cleanup, nil
+}
+
+// withBody sets body and GetBody of a shallow copy of req
+func withBody(req *http.Request, size int64, body func() io.Reader) *http.Request {
+	req = req.WithContext(req.Context())
+
+	req.ContentLength = size
+	req.Body = io.NopCloser(body())
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(body()), nil
+	}
+
+	return req
+}
@@ -0,0 +1,66 @@
+package httprepeater_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/amidgo/repeater"
+	httprepeater "github.com/amidgo/repeater/http"
+)
+
+func Test_Do_WithBodySpill(t *testing.T) {
+	t.Parallel()
+
+	for _, memLimit := range []int64{100, 4} {
+		var calls atomic.Int64
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != "payload" {
+				t.Errorf("unexpected body %q", body)
+			}
+
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+
+		dir := t.TempDir()
+
+		rp := httprepeater.New(
+			repeater.New(repeater.ConstantProgression(0)),
+			httprepeater.WithBodySpill(memLimit, dir),
+		)
+
+		req, err := http.NewRequest(http.MethodPost, srv.URL, io.NopCloser(strings.NewReader("payload")))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := rp.Do(srv.Client(), req, 2)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		resp.Body.Close()
+		srv.Close()
+
+		if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+			t.Fatalf("unexpected response %d after %d calls, memory limit %d", resp.StatusCode, calls.Load(), memLimit)
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(entries) != 0 {
+			t.Fatalf("temporary body file is not removed, memory limit %d", memLimit)
+		}
+	}
+}
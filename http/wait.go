@@ -0,0 +1,41 @@
+package httprepeater
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/amidgo/repeater"
+)
+
+// WaitForHTTP waits until a GET request to url with client gets a response with one of statuses,
+// any 2xx status if statuses are empty, see repeater.WaitForFunc
+func WaitForHTTP(ctx context.Context, client *http.Client, url string, statuses []int, opts ...repeater.WaitOption) error {
+	return repeater.WaitForFunc(ctx, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+
+		discardResponse(resp)
+
+		if !acceptableStatus(resp.StatusCode, statuses) {
+			return &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+		}
+
+		return nil
+	}, opts...)
+}
+
+func acceptableStatus(status int, statuses []int) bool {
+	if len(statuses) == 0 {
+		return status >= 200 && status < 300
+	}
+
+	return slices.Contains(statuses, status)
+}
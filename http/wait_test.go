@@ -0,0 +1,54 @@
+package httprepeater_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httprepeater "github.com/amidgo/repeater/http"
+)
+
+func Test_WaitForHTTP(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := httprepeater.WaitForHTTP(ctx, srv.Client(), srv.URL, nil)
+	if err != nil || calls.Load() != 2 {
+		t.Fatalf("unexpected result, error %v after %d calls", err, calls.Load())
+	}
+
+	err = httprepeater.WaitForHTTP(ctx, srv.Client(), srv.URL, []int{http.StatusUnauthorized})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	err = httprepeater.WaitForHTTP(ctx, srv.Client(), srv.URL, nil)
+
+	statusErr := &httprepeater.StatusError{}
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected status error, actual %v", err)
+	}
+}
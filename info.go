@@ -0,0 +1,66 @@
+package repeater
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Info describes the current attempt of a repeat to the code called by the repeat func
+type Info struct {
+	// index of the attempt, zero is the initial call
+	Attempt uint64
+	// number of allowed attempts, including the initial one
+	MaxAttempts uint64
+	// time since the repeat start
+	Elapsed time.Duration
+	// progression duration before the next retry if the attempt fails,
+	// the repeat func may override it, e.g. with RepeatDelay
+	NextBackoff time.Duration
+}
+
+// Retry reports whether the attempt is a retry rather than the initial call
+func (i Info) Retry() bool {
+	return i.Attempt > 0
+}
+
+type infoKey struct{}
+
+// WithContextInfo makes the repeat loop pass Info of every attempt to the repeat func context,
+// so code deep in the call stack, e.g. an ORM or an HTTP middleware, may read it with InfoFromContext.
+// It is disabled by default because every attempt allocates a new context
+func WithContextInfo() Option {
+	return func(r *Repeater) {
+		r.contextInfo = true
+	}
+}
+
+// ContextWithInfo returns a copy of ctx carrying info
+func ContextWithInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// InfoFromContext returns Info of the attempt ctx was passed to, ok is false outside of repeats with WithContextInfo
+func InfoFromContext(ctx context.Context) (info Info, ok bool) {
+	info, ok = ctx.Value(infoKey{}).(Info)
+
+	return info, ok
+}
+
+func (r *Repeater) attemptContext(ctx context.Context, state *repeatState) context.Context {
+	if !r.contextInfo {
+		return ctx
+	}
+
+	maxAttempts := state.retryCount + 1
+	if state.retryCount == math.MaxUint64 {
+		maxAttempts = state.retryCount
+	}
+
+	return ContextWithInfo(ctx, Info{
+		Attempt:     state.attempt,
+		MaxAttempts: maxAttempts,
+		Elapsed:     state.elapsed(),
+		NextBackoff: r.progressionFor(ctx).Duration(state.attempt),
+	})
+}
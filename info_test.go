@@ -0,0 +1,55 @@
+package repeater_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_WithContextInfo(t *testing.T) {
+	t.Parallel()
+
+	rp := repeater.New(repeater.NewArifmeticProgression(time.Millisecond, time.Millisecond), repeater.WithContextInfo())
+
+	var infos []repeater.Info
+
+	rp.RepeatContext(context.Background(), func(ctx context.Context) bool {
+		info, ok := repeater.InfoFromContext(ctx)
+		if !ok {
+			t.Fatal("expected info in the repeat func context")
+		}
+
+		infos = append(infos, info)
+
+		return false
+	}, 2)
+
+	if len(infos) != 3 {
+		t.Fatalf("wrong infos count, expected 3, actual %d", len(infos))
+	}
+
+	for i, info := range infos {
+		if info.Attempt != uint64(i) || info.MaxAttempts != 3 || info.Retry() != (i > 0) {
+			t.Fatalf("wrong info of attempt %d, actual %+v", i, info)
+		}
+
+		expectedBackoff := time.Millisecond * time.Duration(i+1)
+		if info.NextBackoff != expectedBackoff {
+			t.Fatalf("wrong next backoff of attempt %d, expected %s, actual %s", i, expectedBackoff, info.NextBackoff)
+		}
+
+		if i > 0 && info.Elapsed <= infos[i-1].Elapsed {
+			t.Fatalf("elapsed doesn't grow, attempt %d %s, previous %s", i, info.Elapsed, infos[i-1].Elapsed)
+		}
+	}
+
+	repeater.New(repeater.ConstantProgression(0)).RepeatContext(context.Background(), func(ctx context.Context) bool {
+		if _, ok := repeater.InfoFromContext(ctx); ok {
+			t.Fatal("unexpected info without WithContextInfo")
+		}
+
+		return true
+	}, 0)
+}
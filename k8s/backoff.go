@@ -0,0 +1,73 @@
+package k8srepeater
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+// Backoff is a copy of k8s.io/apimachinery/pkg/util/wait.Backoff with the same fields in the same order,
+// so the types convert to each other with wait.Backoff(b) and k8srepeater.Backoff(b)
+// without the module depending on apimachinery
+type Backoff struct {
+	Duration time.Duration
+	Factor   float64
+	Jitter   float64
+	Steps    int
+	Cap      time.Duration
+}
+
+// ToConfig converts b to a repeater config, factors greater than 1 make exponential backoff
+// and constant otherwise, Steps counts calls of the condition, so it is the retry count plus one.
+// wait.Backoff adds jitter in [d, d + d*jitter] range while repeater spreads it in both directions,
+// and it stops growing at Cap while repeater keeps retrying with Cap delays
+func ToConfig(b Backoff) repeater.Config {
+	cfg := repeater.Config{
+		Backoff:    repeater.ConstantBackoff,
+		Initial:    b.Duration,
+		Cap:        b.Cap,
+		Jitter:     b.Jitter,
+		MaxRetries: uint64(max(b.Steps-1, 0)),
+	}
+
+	if b.Factor > 1 {
+		cfg.Backoff = repeater.ExponentialBackoff
+		cfg.Factor = b.Factor
+	}
+
+	return cfg
+}
+
+// ToPolicy builds the policy of ToConfig(b), opts are passed to repeater.New
+func ToPolicy(b Backoff, opts ...repeater.Option) (repeater.Policy, error) {
+	return ToConfig(b).Build(opts...)
+}
+
+// FromConfig converts a constant or exponential repeater config to Backoff,
+// max elapsed is dropped because wait.Backoff has no such limit
+func FromConfig(cfg repeater.Config) (Backoff, error) {
+	err := cfg.Validate()
+	if err != nil {
+		return Backoff{}, err
+	}
+
+	b := Backoff{
+		Duration: cfg.Initial,
+		Factor:   1,
+		Jitter:   cfg.Jitter,
+		Steps:    int(min(cfg.MaxRetries, uint64(math.MaxInt-1))) + 1,
+		Cap:      cfg.Cap,
+	}
+
+	switch cfg.Backoff {
+	case repeater.ConstantBackoff:
+	case repeater.ExponentialBackoff:
+		b.Factor = cfg.Factor
+	default:
+		return Backoff{}, fmt.Errorf("%w: %q backoff can't be converted to wait.Backoff", repeater.ErrInvalidConfig, cfg.Backoff)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,79 @@
+package k8srepeater_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	k8srepeater "github.com/amidgo/repeater/k8s"
+)
+
+func Test_ToConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := k8srepeater.ToConfig(k8srepeater.Backoff{
+		Duration: time.Millisecond * 10,
+		Factor:   2,
+		Jitter:   0.1,
+		Steps:    5,
+		Cap:      time.Second,
+	})
+
+	expected := repeater.Config{
+		Backoff:    repeater.ExponentialBackoff,
+		Initial:    time.Millisecond * 10,
+		Factor:     2,
+		Cap:        time.Second,
+		Jitter:     0.1,
+		MaxRetries: 4,
+	}
+
+	if cfg != expected {
+		t.Fatalf("wrong config, expected %+v, actual %+v", expected, cfg)
+	}
+
+	policy, err := k8srepeater.ToPolicy(k8srepeater.Backoff{Duration: time.Second, Steps: 1})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if policy.RetryCount() != 0 || policy.Repeater().Progression().Duration(3) != time.Second {
+		t.Fatalf("unexpected policy, retry count %d", policy.RetryCount())
+	}
+}
+
+func Test_FromConfig(t *testing.T) {
+	t.Parallel()
+
+	b, err := k8srepeater.FromConfig(repeater.Config{
+		Backoff:    repeater.ExponentialBackoff,
+		Initial:    time.Millisecond * 10,
+		Factor:     2,
+		Cap:        time.Second,
+		MaxRetries: 4,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := k8srepeater.Backoff{Duration: time.Millisecond * 10, Factor: 2, Steps: 5, Cap: time.Second}
+	if b != expected {
+		t.Fatalf("wrong backoff, expected %+v, actual %+v", expected, b)
+	}
+
+	if k8srepeater.ToConfig(b) != (repeater.Config{
+		Backoff:    repeater.ExponentialBackoff,
+		Initial:    time.Millisecond * 10,
+		Factor:     2,
+		Cap:        time.Second,
+		MaxRetries: 4,
+	}) {
+		t.Fatal("config doesn't survive a round trip")
+	}
+
+	_, err = k8srepeater.FromConfig(repeater.Config{Backoff: repeater.FibonacciBackoff, Initial: time.Second})
+	if !errors.Is(err, repeater.ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, actual %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package repeater
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type loopOptions struct {
+	stableAfter     time.Duration
+	successInterval time.Duration
+	health          *HealthMonitor
+	// stopOnSuccess ends the loop after the first successful call, it is used by Supervise
+	stopOnSuccess bool
+}
+
+type LoopOption func(o *loopOptions)
+
+// WithStableAfter resets the progression when a failed op call ran at least stableAfter,
+// so a worker which failed after hours of work restarts with the initial delay, zero disables it
+func WithStableAfter(stableAfter time.Duration) LoopOption {
+	return func(o *loopOptions) {
+		o.stableAfter = stableAfter
+	}
+}
+
+// WithSuccessInterval sleeps interval after every successful op call, op is called again immediately by default
+func WithSuccessInterval(interval time.Duration) LoopOption {
+	return func(o *loopOptions) {
+		o.successInterval = interval
+	}
+}
+
+// Loop calls op until ctx is done or op returns an Abort or a Stop error, it is meant for long-lived workers
+// which must never give up, unlike repeats it has no retry count.
+// Failed calls sleep durations of progression, a successful call resets the progression.
+// Loop returns ctx.Err() or the Abort or Stop error returned by op
+func Loop(ctx context.Context, progression DurationProgression, op RepeatErrFunc, opts ...LoopOption) error {
+	var o loopOptions
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return loop(ctx, progression, op, o)
+}
+
+func loop(ctx context.Context, progression DurationProgression, op RepeatErrFunc, o loopOptions) error {
+	timer := sleepTimer{}
+	defer timer.stop()
+
+	defer o.health.stopped()
+
+	var failures uint64
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		start := time.Now()
+
+		o.health.started()
+
+		err := op(ctx)
+
+		if IsAbort(err) {
+			o.health.failed(err, false)
+
+			return err
+		}
+
+		if errors.Is(err, ErrStopped) {
+			return err
+		}
+
+		sleepTime := o.successInterval
+
+		if err != nil {
+			reset := o.stableAfter > 0 && time.Since(start) >= o.stableAfter
+			if reset {
+				failures = 0
+			}
+
+			o.health.failed(err, reset)
+
+			sleepTime = progression.Duration(failures)
+			failures++
+		} else {
+			o.health.succeeded()
+
+			if o.stopOnSuccess {
+				return nil
+			}
+
+			failures = 0
+		}
+
+		if sleepTime <= 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.after(sleepTime):
+		}
+	}
+}
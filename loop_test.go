@@ -0,0 +1,106 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Loop(t *testing.T) {
+	t.Parallel()
+
+	t.Run("abort", func(t *testing.T) {
+		t.Parallel()
+
+		results := []error{io.ErrUnexpectedEOF, io.ErrUnexpectedEOF, nil, io.ErrUnexpectedEOF, repeater.Abort(io.EOF)}
+
+		var (
+			calls []time.Time
+			start = time.Now()
+		)
+
+		err := repeater.Loop(context.Background(), repeater.NewArifmeticProgression(time.Millisecond*10, time.Millisecond*10), func(context.Context) error {
+			calls = append(calls, time.Now())
+
+			return results[len(calls)-1]
+		})
+
+		if !errors.Is(err, io.EOF) || !repeater.IsAbort(err) {
+			t.Fatalf("expected abort error, actual %v", err)
+		}
+
+		if len(calls) != len(results) {
+			t.Fatalf("wrong calls, expected %d, actual %d", len(results), len(calls))
+		}
+
+		// 10ms and 20ms after failures, no sleep after success, 10ms after reset progression
+		gaps := make([]time.Duration, 0, len(calls))
+		prev := start
+
+		for _, call := range calls {
+			gaps = append(gaps, call.Sub(prev).Round(time.Millisecond*10))
+			prev = call
+		}
+
+		expectedGaps := []time.Duration{0, time.Millisecond * 10, time.Millisecond * 20, 0, time.Millisecond * 10}
+		if !slices.Equal(expectedGaps, gaps) {
+			t.Fatalf("wrong gaps between calls, expected %v, actual %v", expectedGaps, gaps)
+		}
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*30)
+		defer cancel()
+
+		err := repeater.Loop(ctx, repeater.ConstantProgression(time.Millisecond), func(context.Context) error {
+			return nil
+		}, repeater.WithSuccessInterval(time.Millisecond))
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected context error, actual %v", err)
+		}
+	})
+
+	t.Run("stable after", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			calls int
+			last  time.Time
+			gaps  []time.Duration
+		)
+
+		repeater.Loop(context.Background(), repeater.NewExponentialProgression(time.Millisecond*10, 4), func(context.Context) error {
+			calls++
+
+			if !last.IsZero() {
+				gaps = append(gaps, time.Since(last).Round(time.Millisecond*10))
+			}
+
+			if calls == 4 {
+				return repeater.Abort(io.EOF)
+			}
+
+			// the third call runs long enough to reset the progression
+			if calls == 3 {
+				time.Sleep(time.Millisecond * 30)
+			}
+
+			last = time.Now()
+
+			return io.ErrUnexpectedEOF
+		}, repeater.WithStableAfter(time.Millisecond*25))
+
+		expectedGaps := []time.Duration{time.Millisecond * 10, time.Millisecond * 40, time.Millisecond * 10}
+		if !slices.Equal(expectedGaps, gaps) {
+			t.Fatalf("wrong gaps between calls, expected %v, actual %v", expectedGaps, gaps)
+		}
+	})
+}
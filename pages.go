@@ -0,0 +1,59 @@
+package repeater
+
+import (
+	"context"
+	"iter"
+)
+
+// PageFunc fetches the page at cursor, an empty next cursor means the last page
+type PageFunc[T any] func(ctx context.Context, cursor string) (items []T, next string, err error)
+
+// Pages yields items of every page starting from the empty cursor, each page fetch is repeated
+// with policy independently, so a failed fetch is retried from the last good cursor without refetching
+// earlier pages. When a fetch gives up, its *Error is yielded with the zero item and the iteration stops:
+//
+//	for user, err := range repeater.Pages(ctx, policy, client.ListUsers) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func Pages[T any](ctx context.Context, policy Policy, fetch PageFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var cursor string
+
+		for {
+			var (
+				items []T
+				next  string
+			)
+
+			err := policy.RepeatErr(ctx, func(ctx context.Context) error {
+				var err error
+
+				items, next, err = fetch(ctx, cursor)
+
+				return err
+			})
+			if err != nil {
+				var zero T
+
+				yield(zero, err)
+
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if next == "" {
+				return
+			}
+
+			cursor = next
+		}
+	}
+}
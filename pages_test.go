@@ -0,0 +1,90 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Pages(t *testing.T) {
+	t.Parallel()
+
+	errTemporary := errors.New("temporary")
+
+	pages := map[string][]int{"": {1, 2}, "2": {3, 4}, "4": {5}}
+	nexts := map[string]string{"": "2", "2": "4", "4": ""}
+
+	var cursors []string
+
+	fetch := func(_ context.Context, cursor string) ([]int, string, error) {
+		cursors = append(cursors, cursor)
+
+		// the second page fails once
+		if cursor == "2" && len(cursors) == 2 {
+			return nil, "", errTemporary
+		}
+
+		return pages[cursor], nexts[cursor], nil
+	}
+
+	policy := repeater.NewPolicy(repeater.WithMaxRetries(1))
+
+	var items []int
+
+	for item, err := range repeater.Pages(context.Background(), policy, fetch) {
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		items = append(items, item)
+	}
+
+	if !slices.Equal([]int{1, 2, 3, 4, 5}, items) {
+		t.Fatalf("wrong items %v", items)
+	}
+
+	if !slices.Equal([]string{"", "2", "2", "4"}, cursors) {
+		t.Fatalf("wrong cursors %q", cursors)
+	}
+}
+
+func Test_Pages_GiveUp(t *testing.T) {
+	t.Parallel()
+
+	errTemporary := errors.New("temporary")
+
+	fetch := func(_ context.Context, cursor string) ([]int, string, error) {
+		if cursor == "" {
+			return []int{1}, "next", nil
+		}
+
+		return nil, "", errTemporary
+	}
+
+	var (
+		items []int
+		errs  []error
+	)
+
+	for item, err := range repeater.Pages(context.Background(), repeater.NewPolicy(repeater.WithMaxRetries(2)), fetch) {
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	if !slices.Equal([]int{1}, items) || len(errs) != 1 {
+		t.Fatalf("unexpected items %v and errors %v", items, errs)
+	}
+
+	var repeatErr *repeater.Error
+	if !errors.As(errs[0], &repeatErr) || repeatErr.Attempts != 3 || !errors.Is(errs[0], errTemporary) {
+		t.Fatalf("unexpected error %v", errs[0])
+	}
+}
@@ -0,0 +1,152 @@
+package repeater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Policy is a Repeater bound to a retry count
+type Policy struct {
+	repeater   *Repeater
+	retryCount uint64
+}
+
+type policyOptions struct {
+	progression DurationProgression
+	retryCount  uint64
+	opts        []Option
+}
+
+type PolicyOption func(p *policyOptions)
+
+// WithProgression sets policy progression, zero ConstantProgression is used by default
+func WithProgression(progression DurationProgression) PolicyOption {
+	return func(p *policyOptions) {
+		p.progression = progression
+	}
+}
+
+// WithMaxRetries sets policy retry count, zero by default
+func WithMaxRetries(retryCount uint64) PolicyOption {
+	return func(p *policyOptions) {
+		p.retryCount = retryCount
+	}
+}
+
+// WithRepeaterOptions passes opts to New, e.g. recorders or WithMaxElapsed
+func WithRepeaterOptions(opts ...Option) PolicyOption {
+	return func(p *policyOptions) {
+		p.opts = append(p.opts, opts...)
+	}
+}
+
+func NewPolicy(opts ...PolicyOption) Policy {
+	p := policyOptions{
+		progression: ConstantProgression(0),
+	}
+
+	for _, opt := range opts {
+		opt(&p)
+	}
+
+	return Policy{
+		repeater:   New(p.progression, p.opts...),
+		retryCount: p.retryCount,
+	}
+}
+
+// Validate reports zero Policy values which were not built by NewPolicy or Config.Build
+// and policies which can't sleep sensibly, e.g. with negative durations of the progressions of this package
+// or a cap below the initial duration, progressions of other packages are not checked
+func (p Policy) Validate() error {
+	if p.repeater == nil || p.repeater.progression == nil {
+		return fmt.Errorf("%w: policy has no progression", ErrInvalidConfig)
+	}
+
+	errs := validateProgression(p.repeater.progression, false)
+
+	if p.repeater.maxElapsed < 0 {
+		errs = append(errs, fmt.Errorf("negative max elapsed %s", p.repeater.maxElapsed))
+	}
+
+	if p.repeater.maxTotalBackoff < 0 {
+		errs = append(errs, fmt.Errorf("negative max total backoff %s", p.repeater.maxTotalBackoff))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// validateProgression checks parameters of progressions of this package, capped is set inside CappedProgression
+func validateProgression(progression DurationProgression, capped bool) []error {
+	var errs []error
+
+	switch p := progression.(type) {
+	case ConstantProgression:
+		if p < 0 {
+			errs = append(errs, fmt.Errorf("negative constant %s", time.Duration(p)))
+		}
+	case FibonacciProgression:
+		if p < 0 {
+			errs = append(errs, fmt.Errorf("negative fibonacci initial %s", time.Duration(p)))
+		}
+	case ArifmeticProggression:
+		if p.initial < 0 {
+			errs = append(errs, fmt.Errorf("negative arifmetic initial %s", p.initial))
+		}
+
+		if p.delta < 0 {
+			errs = append(errs, fmt.Errorf("negative arifmetic delta %s makes later backoffs negative", p.delta))
+		}
+	case ExponentialProgression:
+		if p.initial < 0 {
+			errs = append(errs, fmt.Errorf("negative exponential initial %s", p.initial))
+		}
+
+		switch {
+		case p.factor <= 0:
+			errs = append(errs, fmt.Errorf("exponential factor %g must be positive", p.factor))
+		case p.factor < 1 && capped:
+			errs = append(errs, fmt.Errorf("exponential factor %g is less than 1, cap is never reached", p.factor))
+		}
+	case CappedProgression:
+		errs = validateProgression(p.progression, true)
+
+		if initial := p.progression.Duration(0); p.limit < initial {
+			errs = append(errs, fmt.Errorf("cap %s is below initial %s", p.limit, initial))
+		}
+	case JitterProgression:
+		errs = validateProgression(p.progression, capped)
+
+		if p.factor < 0 || p.factor > 1 {
+			errs = append(errs, fmt.Errorf("jitter %g out of [0, 1] range", p.factor))
+		}
+	}
+
+	return errs
+}
+
+func (p Policy) Repeater() *Repeater {
+	return p.repeater
+}
+
+func (p Policy) RetryCount() uint64 {
+	return p.retryCount
+}
+
+func (p Policy) Repeat(rf RepeatFunc) (finished bool) {
+	return p.repeater.Repeat(rf, p.retryCount)
+}
+
+func (p Policy) RepeatContext(ctx context.Context, rfctx RepeatFuncContext) (finished bool) {
+	return p.repeater.RepeatContext(ctx, rfctx, p.retryCount)
+}
+
+func (p Policy) RepeatErr(ctx context.Context, rf RepeatErrFunc) error {
+	return p.repeater.RepeatErr(ctx, rf, p.retryCount)
+}
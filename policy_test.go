@@ -0,0 +1,68 @@
+package repeater_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_NewPolicy(t *testing.T) {
+	t.Parallel()
+
+	recorder := &recorderMock{}
+
+	policy := repeater.NewPolicy(
+		repeater.WithProgression(repeater.ConstantProgression(time.Millisecond)),
+		repeater.WithMaxRetries(2),
+		repeater.WithRepeaterOptions(repeater.WithRecorder(recorder)),
+	)
+
+	if policy.RetryCount() != 2 {
+		t.Fatalf("wrong retry count, expected 2, actual %d", policy.RetryCount())
+	}
+
+	finished := policy.RepeatContext(context.Background(), func(context.Context) bool { return false })
+	if finished {
+		t.Fatal("expected not finished repeat")
+	}
+
+	expectedCalls := []string{
+		"started 0",
+		"finished 0 false",
+		"scheduled 1 1ms",
+		"started 1",
+		"finished 1 false",
+		"scheduled 2 1ms",
+		"started 2",
+		"finished 2 false",
+		"gave up 2 true",
+	}
+
+	if !slices.Equal(expectedCalls, recorder.calls) {
+		t.Fatalf("wrong recorder calls, expected %v, actual %v", expectedCalls, recorder.calls)
+	}
+}
+
+func Test_NewPolicy_Defaults(t *testing.T) {
+	t.Parallel()
+
+	policy := repeater.NewPolicy()
+
+	calls := 0
+
+	finished := policy.Repeat(func() bool {
+		calls++
+
+		return false
+	})
+	if finished {
+		t.Fatal("expected not finished repeat")
+	}
+
+	if calls != 1 {
+		t.Fatalf("wrong calls count, expected 1, actual %d", calls)
+	}
+}
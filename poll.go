@@ -0,0 +1,59 @@
+package repeater
+
+import (
+	"context"
+	"math"
+)
+
+// ConditionFunc reports whether the polled condition is met, a non-nil err stops polling
+type ConditionFunc func(ctx context.Context) (done bool, err error)
+
+func Poll(ctx context.Context, progression DurationProgression, cond ConditionFunc) error {
+	rp := New(progression)
+
+	return rp.Poll(ctx, cond)
+}
+
+// Poll calls cond with delays of the progression until it is done, there is no retry count,
+// polling is limited by ctx and WithMaxElapsed. Returns nil when cond is done, otherwise *Error
+// wrapping the cond error which stopped polling or ctx.Err()
+func (r *Repeater) Poll(ctx context.Context, cond ConditionFunc) error {
+	state := r.startClocked()
+
+	var (
+		attempts uint64
+		condErr  error
+	)
+
+	finished := r.repeatContext(ctx, &state, func(ctx context.Context) bool {
+		attempts++
+
+		done, err := cond(ctx)
+		if err != nil {
+			condErr = err
+			state.aborted = true
+
+			return false
+		}
+
+		return done
+	}, math.MaxUint64)
+	if finished {
+		return nil
+	}
+
+	if condErr == nil {
+		condErr = ctx.Err()
+	}
+
+	return &Error{
+		Attempts: attempts,
+		Elapsed:  state.elapsed(),
+		Last:     condErr,
+		Exceeded: state.exhausted,
+		Aborted:  state.aborted,
+		Cause:    state.errCause(),
+		Context:  state.ctxErr,
+		InSleep:  state.inSleep,
+	}
+}
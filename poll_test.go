@@ -0,0 +1,85 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Poll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("done", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+
+		err := repeater.Poll(context.Background(), repeater.ConstantProgression(time.Millisecond), func(context.Context) (bool, error) {
+			calls++
+
+			return calls == 3, nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if calls != 3 {
+			t.Fatalf("wrong calls, expected 3, actual %d", calls)
+		}
+	})
+
+	t.Run("hard error", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+
+		err := repeater.Poll(context.Background(), repeater.ConstantProgression(0), func(context.Context) (bool, error) {
+			calls++
+
+			if calls == 2 {
+				return false, io.ErrUnexpectedEOF
+			}
+
+			return false, nil
+		})
+
+		if !errors.Is(err, io.ErrUnexpectedEOF) || calls != 2 {
+			t.Fatalf("expected hard error after 2 calls, actual %v after %d calls", err, calls)
+		}
+	})
+
+	t.Run("context deadline", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+		defer cancel()
+
+		err := repeater.Poll(ctx, repeater.ConstantProgression(time.Millisecond), func(context.Context) (bool, error) {
+			return false, nil
+		})
+
+		repeatErr := &repeater.Error{}
+		if !errors.As(err, &repeatErr) || repeatErr.Exceeded || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected context deadline error, actual %v", err)
+		}
+	})
+
+	t.Run("max elapsed", func(t *testing.T) {
+		t.Parallel()
+
+		rp := repeater.New(repeater.ConstantProgression(time.Millisecond), repeater.WithMaxElapsed(time.Millisecond*10))
+
+		err := rp.Poll(context.Background(), func(context.Context) (bool, error) {
+			return false, nil
+		})
+
+		repeatErr := &repeater.Error{}
+		if !errors.As(err, &repeatErr) || !repeatErr.Exceeded {
+			t.Fatalf("expected exceeded error, actual %v", err)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package repeater_test
 
 import (
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -119,5 +120,91 @@ func Test_FibanacciProgression(t *testing.T) {
 			Time:             9,
 			ExpectedDuration: time.Second * 55,
 		},
+		&ProgressionTest{
+			Progression:      repeater.FibonacciProgression(time.Millisecond),
+			Time:             70,
+			ExpectedDuration: math.MaxInt64,
+		},
+		&ProgressionTest{
+			Progression:      repeater.FibonacciProgression(time.Millisecond),
+			Time:             200,
+			ExpectedDuration: math.MaxInt64,
+		},
+	)
+}
+
+func Test_ExponentialProgression(t *testing.T) {
+	tester.RunNamedTesters(t,
+		&ProgressionTest{
+			Progression:      repeater.NewExponentialProgression(time.Second, 2),
+			Time:             0,
+			ExpectedDuration: time.Second,
+		},
+		&ProgressionTest{
+			Progression:      repeater.NewExponentialProgression(time.Second, 2),
+			Time:             3,
+			ExpectedDuration: time.Second * 8,
+		},
+		&ProgressionTest{
+			Progression:      repeater.NewExponentialProgression(time.Millisecond*100, 1.5),
+			Time:             2,
+			ExpectedDuration: time.Millisecond * 225,
+		},
+		&ProgressionTest{
+			Progression:      repeater.NewExponentialProgression(time.Second, 2),
+			Time:             100,
+			ExpectedDuration: math.MaxInt64,
+		},
+	)
+}
+
+func Test_CappedProgression(t *testing.T) {
+	tester.RunNamedTesters(t,
+		&ProgressionTest{
+			Progression:      repeater.NewCappedProgression(repeater.NewExponentialProgression(time.Second, 2), time.Second*5),
+			Time:             2,
+			ExpectedDuration: time.Second * 4,
+		},
+		&ProgressionTest{
+			Progression:      repeater.NewCappedProgression(repeater.NewExponentialProgression(time.Second, 2), time.Second*5),
+			Time:             3,
+			ExpectedDuration: time.Second * 5,
+		},
 	)
 }
+
+func Test_JitterProgression(t *testing.T) {
+	progression := repeater.NewJitterProgression(repeater.ConstantProgression(time.Second), 0.2)
+
+	for attempt := range uint64(100) {
+		d := progression.Duration(attempt)
+
+		if d < time.Millisecond*800 || d > time.Millisecond*1200 {
+			t.Fatalf("duration %s out of jitter range", d)
+		}
+	}
+
+	noJitter := repeater.NewJitterProgression(repeater.ConstantProgression(time.Second), 0)
+	if d := noJitter.Duration(0); d != time.Second {
+		t.Fatalf("wrong duration with zero jitter, expected %s, actual %s", time.Second, d)
+	}
+}
+
+func Test_JitterProgression_Saturated(t *testing.T) {
+	progressions := []repeater.DurationProgression{
+		repeater.NewExponentialProgression(time.Second, 2),
+		repeater.FibonacciProgression(time.Millisecond),
+	}
+
+	for _, progression := range progressions {
+		jitter := repeater.NewJitterProgression(progression, 0.2)
+
+		for range 100 {
+			d := jitter.Duration(200)
+
+			if d < time.Duration(float64(math.MaxInt64)*0.8) {
+				t.Fatalf("duration %s of saturated %T out of jitter range", d, progression)
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package repeater
+
+import (
+	"context"
+	"time"
+)
+
+// Recorder observes the repeat loop, all methods are called synchronously
+// from the goroutine running the repeat, so implementations must be fast.
+// attempt zero is the initial call, retries start from 1.
+// elapsed is measured from the monotonic repeat start shared by all recorders.
+type Recorder interface {
+	// AttemptStarted is called right before the repeat func
+	AttemptStarted(ctx context.Context, attempt uint64, elapsed time.Duration)
+	// AttemptFinished is called right after the repeat func returned
+	AttemptFinished(ctx context.Context, attempt uint64, elapsed time.Duration, finished bool)
+	// RetryScheduled is called before sleeping delay prior to the attempt
+	RetryScheduled(ctx context.Context, attempt uint64, elapsed, delay time.Duration)
+	// GaveUp is called when the repeat stopped without success with the last made attempt,
+	// exhausted is false if the repeat was stopped by context, even before the first attempt,
+	// or aborted by the caller
+	GaveUp(ctx context.Context, attempt uint64, elapsed time.Duration, exhausted bool)
+}
+
+// StopRecorder is an optional Recorder extension observing repeats stopped by a Stop error,
+// Stopped is called instead of GaveUp, recorders without it get GaveUp with exhausted false
+type StopRecorder interface {
+	Stopped(ctx context.Context, attempt uint64, elapsed time.Duration)
+}
+
+// ErrorRecorder is an optional Recorder extension observing errors of attempts made by RepeatErr,
+// AttemptFailed is called instead of AttemptFinished for an attempt which returned an error,
+// recorders without it get AttemptFinished with finished false
+type ErrorRecorder interface {
+	AttemptFailed(ctx context.Context, attempt uint64, elapsed time.Duration, err error)
+}
+
+// cancelRecorder observes repeats stopped by context, canceled is called instead of GaveUp,
+// recorders without it get GaveUp with exhausted false
+type cancelRecorder interface {
+	canceled(ctx context.Context, attempt uint64, elapsed time.Duration)
+}
+
+// WithRecorder adds a recorder to the repeater, may be used many times
+func WithRecorder(recorder Recorder) Option {
+	return func(r *Repeater) {
+		r.recorder = append(r.recorder, recorder)
+	}
+}
+
+// Recorder returns recorders of the repeater, including the one collecting Stats, as one Recorder,
+// so adapters of retry loops running outside the repeater, e.g. in SDK clients, report to them
+func (r *Repeater) Recorder() Recorder {
+	return r.recorder
+}
+
+type recorders []Recorder
+
+func (rs recorders) AttemptStarted(ctx context.Context, attempt uint64, elapsed time.Duration) {
+	for _, r := range rs {
+		r.AttemptStarted(ctx, attempt, elapsed)
+	}
+}
+
+func (rs recorders) AttemptFinished(ctx context.Context, attempt uint64, elapsed time.Duration, finished bool) {
+	for _, r := range rs {
+		r.AttemptFinished(ctx, attempt, elapsed, finished)
+	}
+}
+
+func (rs recorders) RetryScheduled(ctx context.Context, attempt uint64, elapsed, delay time.Duration) {
+	for _, r := range rs {
+		r.RetryScheduled(ctx, attempt, elapsed, delay)
+	}
+}
+
+func (rs recorders) GaveUp(ctx context.Context, attempt uint64, elapsed time.Duration, exhausted bool) {
+	for _, r := range rs {
+		r.GaveUp(ctx, attempt, elapsed, exhausted)
+	}
+}
+
+func (rs recorders) Stopped(ctx context.Context, attempt uint64, elapsed time.Duration) {
+	for _, r := range rs {
+		if stopRecorder, ok := r.(StopRecorder); ok {
+			stopRecorder.Stopped(ctx, attempt, elapsed)
+		} else {
+			r.GaveUp(ctx, attempt, elapsed, false)
+		}
+	}
+}
+
+func (rs recorders) canceled(ctx context.Context, attempt uint64, elapsed time.Duration) {
+	for _, r := range rs {
+		if cancelRecorder, ok := r.(cancelRecorder); ok {
+			cancelRecorder.canceled(ctx, attempt, elapsed)
+		} else {
+			r.GaveUp(ctx, attempt, elapsed, false)
+		}
+	}
+}
+
+func (rs recorders) AttemptFailed(ctx context.Context, attempt uint64, elapsed time.Duration, err error) {
+	for _, r := range rs {
+		if errorRecorder, ok := r.(ErrorRecorder); ok {
+			errorRecorder.AttemptFailed(ctx, attempt, elapsed, err)
+		} else {
+			r.AttemptFinished(ctx, attempt, elapsed, false)
+		}
+	}
+}
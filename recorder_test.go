@@ -0,0 +1,469 @@
+package repeater_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+type recorderMock struct {
+	calls []string
+}
+
+func (r *recorderMock) AttemptStarted(_ context.Context, attempt uint64, _ time.Duration) {
+	r.calls = append(r.calls, fmt.Sprintf("started %d", attempt))
+}
+
+func (r *recorderMock) AttemptFinished(_ context.Context, attempt uint64, _ time.Duration, finished bool) {
+	r.calls = append(r.calls, fmt.Sprintf("finished %d %t", attempt, finished))
+}
+
+func (r *recorderMock) RetryScheduled(_ context.Context, attempt uint64, _, delay time.Duration) {
+	r.calls = append(r.calls, fmt.Sprintf("scheduled %d %s", attempt, delay))
+}
+
+func (r *recorderMock) GaveUp(_ context.Context, attempt uint64, _ time.Duration, exhausted bool) {
+	r.calls = append(r.calls, fmt.Sprintf("gave up %d %t", attempt, exhausted))
+}
+
+func Test_WithRecorder(t *testing.T) {
+	t.Parallel()
+
+	first, second := &recorderMock{}, &recorderMock{}
+
+	rp := repeater.New(
+		repeater.ConstantProgression(time.Millisecond),
+		repeater.WithRecorder(first),
+		repeater.WithRecorder(second),
+	)
+
+	finished := rp.Repeat(func() bool { return false }, 1)
+	if finished {
+		t.Fatal("expected not finished repeat")
+	}
+
+	expectedCalls := []string{
+		"started 0",
+		"finished 0 false",
+		"scheduled 1 1ms",
+		"started 1",
+		"finished 1 false",
+		"gave up 1 true",
+	}
+
+	if !slices.Equal(expectedCalls, first.calls) {
+		t.Fatalf("wrong first recorder calls, expected %v, actual %v", expectedCalls, first.calls)
+	}
+
+	if !slices.Equal(expectedCalls, second.calls) {
+		t.Fatalf("wrong second recorder calls, expected %v, actual %v", expectedCalls, second.calls)
+	}
+}
+
+type stopRecorderMock struct {
+	recorderMock
+}
+
+func (r *stopRecorderMock) Stopped(_ context.Context, attempt uint64, _ time.Duration) {
+	r.calls = append(r.calls, fmt.Sprintf("stopped %d", attempt))
+}
+
+func Test_WithRecorder_Stopped(t *testing.T) {
+	t.Parallel()
+
+	plain, stop := &recorderMock{}, &stopRecorderMock{}
+	buf := &bytes.Buffer{}
+
+	rp := repeater.New(
+		repeater.ConstantProgression(0),
+		repeater.WithRecorder(plain),
+		repeater.WithRecorder(stop),
+		repeater.WithRecorder(repeater.NewSlogRecorder(slog.New(slog.NewTextHandler(buf, nil)))),
+	)
+
+	rp.RepeatErr(context.Background(), func(context.Context) error { return repeater.Stop() }, 1)
+
+	expectedCalls := []string{"started 0", "finished 0 false", "gave up 0 false"}
+
+	if !slices.Equal(expectedCalls, plain.calls) {
+		t.Fatalf("wrong plain recorder calls, expected %v, actual %v", expectedCalls, plain.calls)
+	}
+
+	expectedCalls = []string{"started 0", "finished 0 false", "stopped 0"}
+
+	if !slices.Equal(expectedCalls, stop.calls) {
+		t.Fatalf("wrong stop recorder calls, expected %v, actual %v", expectedCalls, stop.calls)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, `level=INFO msg="repeat stopped" attempt=0`) || strings.Contains(output, "level=WARN") {
+		t.Fatalf("wrong log output %s", output)
+	}
+}
+
+func Test_WithRecorder_ContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	recorder := &recorderMock{}
+
+	rp := repeater.New(
+		repeater.ConstantProgression(time.Second),
+		repeater.WithRecorder(recorder),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	finished := rp.RepeatContext(ctx, func(context.Context) bool { return false }, 3)
+	if finished {
+		t.Fatal("expected not finished repeat")
+	}
+
+	expectedCalls := []string{
+		"started 0",
+		"finished 0 false",
+		"scheduled 1 1s",
+		"gave up 0 false",
+	}
+
+	if !slices.Equal(expectedCalls, recorder.calls) {
+		t.Fatalf("wrong recorder calls, expected %v, actual %v", expectedCalls, recorder.calls)
+	}
+}
+
+func Test_WithRecorder_ContextDoneBeforeAttempt(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	t.Run("checked", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &recorderMock{}
+
+		rp := repeater.New(
+			repeater.ConstantProgression(0),
+			repeater.WithRecorder(recorder),
+		)
+
+		calls := 0
+
+		finished := rp.RepeatContext(ctx, func(context.Context) bool {
+			calls++
+
+			return true
+		}, 3)
+		if finished {
+			t.Fatal("expected not finished repeat")
+		}
+
+		if calls != 0 {
+			t.Fatalf("repeat func called %d times, expected no calls", calls)
+		}
+
+		expectedCalls := []string{"gave up 0 false"}
+
+		if !slices.Equal(expectedCalls, recorder.calls) {
+			t.Fatalf("wrong recorder calls, expected %v, actual %v", expectedCalls, recorder.calls)
+		}
+	})
+
+	t.Run("checked after failed attempt", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &recorderMock{}
+
+		rp := repeater.New(
+			repeater.ConstantProgression(0),
+			repeater.WithRecorder(recorder),
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		finished := rp.RepeatContext(ctx, func(context.Context) bool {
+			cancel()
+
+			return false
+		}, 3)
+		if finished {
+			t.Fatal("expected not finished repeat")
+		}
+
+		expectedCalls := []string{
+			"started 0",
+			"finished 0 false",
+			"gave up 0 false",
+		}
+
+		if !slices.Equal(expectedCalls, recorder.calls) {
+			t.Fatalf("wrong recorder calls, expected %v, actual %v", expectedCalls, recorder.calls)
+		}
+	})
+
+	t.Run("without context check", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &recorderMock{}
+
+		rp := repeater.New(
+			repeater.ConstantProgression(0),
+			repeater.WithRecorder(recorder),
+			repeater.WithoutContextCheck(),
+		)
+
+		finished := rp.RepeatContext(ctx, func(context.Context) bool { return false }, 1)
+		if finished {
+			t.Fatal("expected not finished repeat")
+		}
+
+		expectedCalls := []string{
+			"started 0",
+			"finished 0 false",
+			"scheduled 1 0s",
+			"started 1",
+			"finished 1 false",
+			"gave up 1 true",
+		}
+
+		if !slices.Equal(expectedCalls, recorder.calls) {
+			t.Fatalf("wrong recorder calls, expected %v, actual %v", expectedCalls, recorder.calls)
+		}
+	})
+}
+
+func Test_WithExpectedLatency(t *testing.T) {
+	t.Parallel()
+
+	recorder := &recorderMock{}
+
+	rp := repeater.New(
+		repeater.ConstantProgression(time.Millisecond*100),
+		repeater.WithRecorder(recorder),
+		repeater.WithExpectedLatency(time.Second),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+
+	finished := rp.RepeatContext(ctx, func(context.Context) bool { return false }, 3)
+	if finished {
+		t.Fatal("expected not finished repeat")
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Millisecond*100 {
+		t.Fatalf("expected repeat to give up without sleeping, elapsed %s", elapsed)
+	}
+
+	expectedCalls := []string{
+		"started 0",
+		"finished 0 false",
+		"gave up 0 false",
+	}
+
+	if !slices.Equal(expectedCalls, recorder.calls) {
+		t.Fatalf("wrong recorder calls, expected %v, actual %v", expectedCalls, recorder.calls)
+	}
+}
+
+func Test_SlogRecorder(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	rp := repeater.New(
+		repeater.ConstantProgression(0),
+		repeater.WithRecorder(repeater.NewSlogRecorder(logger)),
+	)
+
+	rp.Repeat(func() bool { return false }, 1)
+
+	output := buf.String()
+
+	for _, expected := range []string{
+		`msg="repeat retry scheduled" attempt=1 elapsed=`,
+		`delay=0s`,
+		`msg="repeat gave up" attempt=1 elapsed=`,
+		`exhausted=true`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Fatalf("log output doesn't contain %q, output: %s", expected, output)
+		}
+	}
+
+	if strings.Contains(output, "repeat attempt started") {
+		t.Fatalf("debug messages must be filtered, output: %s", output)
+	}
+}
+
+type loggerKey struct{}
+
+func Test_SlogRecorder_WithLoggerFromContext(t *testing.T) {
+	t.Parallel()
+
+	defaultBuf, requestBuf := &bytes.Buffer{}, &bytes.Buffer{}
+
+	defaultLogger := slog.New(slog.NewTextHandler(defaultBuf, nil))
+	requestLogger := slog.New(slog.NewTextHandler(requestBuf, nil)).With(slog.String("request_id", "42"))
+
+	rp := repeater.New(
+		repeater.ConstantProgression(0),
+		repeater.WithRecorder(
+			repeater.NewSlogRecorder(defaultLogger,
+				repeater.WithLoggerFromContext(func(ctx context.Context) *slog.Logger {
+					logger, _ := ctx.Value(loggerKey{}).(*slog.Logger)
+
+					return logger
+				}),
+			),
+		),
+	)
+
+	ctx := context.WithValue(context.Background(), loggerKey{}, requestLogger)
+
+	rp.RepeatContext(ctx, func(context.Context) bool { return false }, 1)
+
+	if !strings.Contains(requestBuf.String(), `msg="repeat gave up" request_id=42 attempt=1`) {
+		t.Fatalf("request logger wasn't used, output: %s", requestBuf)
+	}
+
+	if defaultBuf.Len() != 0 {
+		t.Fatalf("default logger must not be used, output: %s", defaultBuf)
+	}
+
+	rp.RepeatContext(context.Background(), func(context.Context) bool { return false }, 1)
+
+	if !strings.Contains(defaultBuf.String(), `msg="repeat gave up" attempt=1`) {
+		t.Fatalf("default logger wasn't used as fallback, output: %s", defaultBuf)
+	}
+}
+
+type elapsedRecorder struct {
+	elapsed []time.Duration
+}
+
+func (e *elapsedRecorder) AttemptStarted(_ context.Context, _ uint64, elapsed time.Duration) {
+	e.elapsed = append(e.elapsed, elapsed)
+}
+
+func (e *elapsedRecorder) AttemptFinished(_ context.Context, _ uint64, elapsed time.Duration, _ bool) {
+	e.elapsed = append(e.elapsed, elapsed)
+}
+
+func (e *elapsedRecorder) RetryScheduled(_ context.Context, _ uint64, elapsed, _ time.Duration) {
+	e.elapsed = append(e.elapsed, elapsed)
+}
+
+func (e *elapsedRecorder) GaveUp(_ context.Context, _ uint64, elapsed time.Duration, _ bool) {
+	e.elapsed = append(e.elapsed, elapsed)
+}
+
+func Test_WithRecorder_Elapsed(t *testing.T) {
+	t.Parallel()
+
+	recorder := &elapsedRecorder{}
+
+	rp := repeater.New(
+		repeater.ConstantProgression(time.Millisecond*10),
+		repeater.WithRecorder(recorder),
+	)
+
+	rp.Repeat(func() bool { return false }, 2)
+
+	if len(recorder.elapsed) != 9 {
+		t.Fatalf("wrong recorded elapsed count, expected 9, actual %d", len(recorder.elapsed))
+	}
+
+	if !slices.IsSorted(recorder.elapsed) {
+		t.Fatalf("elapsed must be monotonic, actual %v", recorder.elapsed)
+	}
+
+	last := recorder.elapsed[len(recorder.elapsed)-1]
+	if last < time.Millisecond*20 || last > time.Millisecond*30 {
+		t.Fatalf("wrong final elapsed, expected about 20ms, actual %s", last)
+	}
+}
+
+func Test_Repeater_ConcurrentReuse(t *testing.T) {
+	t.Parallel()
+
+	const goroutines = 16
+
+	events := make(chan repeater.AttemptEvent, goroutines*8)
+	logs := &lockedBuffer{}
+
+	rp := repeater.New(
+		repeater.ConstantProgression(time.Millisecond),
+		repeater.WithEventChannel(events, repeater.DropEvents),
+		repeater.WithAuditWriter(logs),
+		repeater.WithTimeline(),
+		repeater.WithRecorder(repeater.NewSlogRecorder(slog.New(slog.NewTextHandler(logs, nil)))),
+	)
+
+	var (
+		wg       sync.WaitGroup
+		finished atomic.Uint64
+	)
+
+	for range goroutines {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			ctx, timeline := repeater.ContextWithTimeline(context.Background())
+
+			calls := 0
+
+			ok := rp.RepeatContext(ctx, func(context.Context) bool {
+				calls++
+
+				return calls == 3
+			}, 3)
+			if ok {
+				finished.Add(1)
+			}
+
+			if len(timeline.Events()) != 9 {
+				t.Errorf("unexpected timeline of a single repeat:\n%s", timeline)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if finished.Load() != goroutines {
+		t.Fatalf("expected %d finished repeats, actual %d", goroutines, finished.Load())
+	}
+
+	stats := rp.Stats()
+	if stats.Calls != goroutines || stats.Attempts != goroutines*3 || stats.Successes != goroutines {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (l *lockedBuffer) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.buf.Write(p)
+}
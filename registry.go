@@ -0,0 +1,71 @@
+package repeater
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	AggressivePolicyName   = "aggressive"
+	StandardPolicyName     = "standard"
+	ConservativePolicyName = "conservative"
+)
+
+var registry = struct {
+	mu       sync.RWMutex
+	policies map[string]Policy
+}{
+	policies: map[string]Policy{
+		AggressivePolicyName: mustBuild(Config{
+			Backoff:    ExponentialBackoff,
+			Initial:    time.Millisecond * 50,
+			Factor:     2,
+			Cap:        time.Second,
+			Jitter:     DefaultJitter,
+			MaxRetries: 10,
+		}),
+		StandardPolicyName: mustBuild(Config{
+			Backoff:    ExponentialBackoff,
+			Initial:    time.Millisecond * 100,
+			Factor:     2,
+			Cap:        time.Second * 10,
+			Jitter:     DefaultJitter,
+			MaxRetries: 5,
+		}),
+		ConservativePolicyName: mustBuild(Config{
+			Backoff:    ExponentialBackoff,
+			Initial:    time.Second,
+			Factor:     2,
+			Cap:        time.Minute,
+			Jitter:     DefaultJitter,
+			MaxRetries: 3,
+		}),
+	},
+}
+
+// Register stores policy by name replacing the previous one,
+// it is intended to be called at startup, but is safe for concurrent use
+func Register(name string, policy Policy) {
+	registry.mu.Lock()
+	registry.policies[name] = policy
+	registry.mu.Unlock()
+}
+
+// Lookup returns policy registered by name,
+// aggressive, standard and conservative policies are registered by default
+func Lookup(name string) (policy Policy, ok bool) {
+	registry.mu.RLock()
+	policy, ok = registry.policies[name]
+	registry.mu.RUnlock()
+
+	return policy, ok
+}
+
+func mustBuild(cfg Config) Policy {
+	policy, err := cfg.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	return policy
+}
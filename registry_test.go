@@ -0,0 +1,51 @@
+package repeater_test
+
+import (
+	"testing"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Registry_Defaults(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{
+		repeater.AggressivePolicyName,
+		repeater.StandardPolicyName,
+		repeater.ConservativePolicyName,
+	} {
+		policy, ok := repeater.Lookup(name)
+		if !ok {
+			t.Fatalf("default policy %s not registered", name)
+		}
+
+		if policy.RetryCount() == 0 {
+			t.Fatalf("default policy %s has zero retry count", name)
+		}
+	}
+}
+
+func Test_Registry_Register(t *testing.T) {
+	t.Parallel()
+
+	_, ok := repeater.Lookup("test-critical")
+	if ok {
+		t.Fatal("unexpected registered policy")
+	}
+
+	policy, err := repeater.ParsePolicy("const(1ms);max=7")
+	if err != nil {
+		t.Fatalf("parse policy: %s", err)
+	}
+
+	repeater.Register("test-critical", policy)
+
+	registered, ok := repeater.Lookup("test-critical")
+	if !ok {
+		t.Fatal("policy not registered")
+	}
+
+	if registered != policy {
+		t.Fatalf("wrong registered policy, expected %+v, actual %+v", policy, registered)
+	}
+}
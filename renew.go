@@ -0,0 +1,81 @@
+package repeater
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrLeaseExpired is returned on the Renew channel when the lease expired before a renewal succeeded
+var ErrLeaseExpired = errors.New("lease expired")
+
+type renewOptions struct {
+	fraction float64
+}
+
+type RenewOption func(o *renewOptions)
+
+// WithRenewFraction renews the lease after fraction of its ttl passed since the last renewal,
+// fraction is expected to be in (0, 1) range, 0.5 by default
+func WithRenewFraction(fraction float64) RenewOption {
+	return func(o *renewOptions) {
+		o.fraction = fraction
+	}
+}
+
+// Renew keeps a lease, a lock or a token with ttl alive by calling renew in a new goroutine
+// every fraction of ttl, failed renewals are retried with policy until the lease expires.
+// The returned channel receives the error when the lease is lost, i.e. policy gave up or the lease expired,
+// and is closed when renewing stops, renewing stops when the lease is lost or ctx is done
+func Renew(ctx context.Context, policy Policy, ttl time.Duration, renew RepeatErrFunc, opts ...RenewOption) <-chan error {
+	o := renewOptions{fraction: 0.5}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	lost := make(chan error, 1)
+
+	go func() {
+		defer close(lost)
+
+		err := renewLoop(ctx, policy, ttl, renew, o)
+		if err != nil {
+			lost <- err
+		}
+	}()
+
+	return lost
+}
+
+func renewLoop(ctx context.Context, policy Policy, ttl time.Duration, renew RepeatErrFunc, o renewOptions) error {
+	timer := sleepTimer{}
+	defer timer.stop()
+
+	renewed := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.after(time.Duration(float64(ttl) * o.fraction)):
+		}
+
+		expiresAt := renewed.Add(ttl)
+
+		leaseCtx, cancel := context.WithDeadline(ctx, expiresAt)
+		err := policy.RepeatErr(leaseCtx, renew)
+		cancel()
+
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil && !time.Now().Before(expiresAt):
+			return errors.Join(ErrLeaseExpired, err)
+		case err != nil:
+			return err
+		}
+
+		renewed = time.Now()
+	}
+}
@@ -0,0 +1,74 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Renew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retries failed renewals", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int64
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		lost := repeater.Renew(ctx, repeater.NewPolicy(repeater.WithMaxRetries(3)), time.Millisecond*40, func(context.Context) error {
+			if calls.Add(1)%2 == 1 {
+				return io.ErrUnexpectedEOF
+			}
+
+			return nil
+		})
+
+		for calls.Load() < 6 {
+			time.Sleep(time.Millisecond)
+		}
+
+		cancel()
+
+		err, ok := <-lost
+		if ok {
+			t.Fatalf("unexpected lease loss %v", err)
+		}
+	})
+
+	t.Run("policy gave up", func(t *testing.T) {
+		t.Parallel()
+
+		lost := repeater.Renew(context.Background(), repeater.NewPolicy(repeater.WithMaxRetries(1)), time.Millisecond*20, func(context.Context) error {
+			return io.ErrUnexpectedEOF
+		})
+
+		err := <-lost
+
+		repeatErr := &repeater.Error{}
+		if !errors.As(err, &repeatErr) || !repeatErr.Exceeded {
+			t.Fatalf("expected exceeded *repeater.Error, actual %v", err)
+		}
+	})
+
+	t.Run("lease expired", func(t *testing.T) {
+		t.Parallel()
+
+		lost := repeater.Renew(
+			context.Background(),
+			repeater.NewPolicy(repeater.WithProgression(repeater.ConstantProgression(time.Millisecond)), repeater.WithMaxRetries(1000)),
+			time.Millisecond*20,
+			func(context.Context) error { return io.ErrUnexpectedEOF },
+		)
+
+		err := <-lost
+		if !errors.Is(err, repeater.ErrLeaseExpired) || !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("expected ErrLeaseExpired, actual %v", err)
+		}
+	})
+}
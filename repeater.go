@@ -2,6 +2,9 @@ package repeater
 
 import (
 	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
 	"time"
 )
 
@@ -35,77 +38,421 @@ func RepeatContext(ctx context.Context, progresstion DurationProgression, rfctx
 	return rp.RepeatContext(ctx, rfctx, retryCount)
 }
 
+// Repeater is safe for concurrent use, every Repeat and RepeatContext call keeps
+// its attempt counter, timer and clock on its own stack,
+// so one Repeater may be shared between goroutines and reused
 type Repeater struct {
 	progression DurationProgression
+	recorder    recorders
+	stats       *statsRecorder
+	maxElapsed  time.Duration
+	// maxTotalBackoff limits the sum of sleeps between attempts
+	maxTotalBackoff time.Duration
+	// expectedLatency is the expected duration of a single repeat func call
+	expectedLatency time.Duration
+	// abortOn lists errors which RepeatErr doesn't retry
+	abortOn []error
+	// giveUpAfterSame is the number of consecutive same errors which stops RepeatErr
+	giveUpAfterSame uint64
+	sameErr         func(prev, err error) bool
+	// historyKeep is the number of first and last attempt errors kept by RepeatErr
+	historyKeep int
+	// controller pauses and kicks repeats, nil means no control
+	controller *Controller
+	// contextInfo enables Info in the repeat func context
+	contextInfo bool
+	// skipContextCheck disables ctx checks before attempts in RepeatContext
+	skipContextCheck bool
+	// clocked is set when max elapsed, context info or recorders other than stats need elapsed time
+	clocked bool
 }
 
-func New(progression DurationProgression) *Repeater {
-	return &Repeater{progression: progression}
+type Option func(r *Repeater)
+
+// WithMaxElapsed stops repeating when the next retry would start later than maxElapsed
+// after the initial call, zero means no limit
+func WithMaxElapsed(maxElapsed time.Duration) Option {
+	return func(r *Repeater) {
+		r.maxElapsed = maxElapsed
+	}
 }
 
-func (r *Repeater) Repeat(rf RepeatFunc, retryCount uint64) (finished bool) {
-	finished = rf()
-	if finished {
-		return true
+// WithMaxTotalBackoff stops repeating when the sum of sleeps between attempts would exceed maxTotalBackoff,
+// unlike WithMaxElapsed the time spent in the repeat func is not counted, zero means no limit.
+// RepeatErr returns *Error wrapping ErrMaxTotalBackoff when the limit is reached
+func WithMaxTotalBackoff(maxTotalBackoff time.Duration) Option {
+	return func(r *Repeater) {
+		r.maxTotalBackoff = maxTotalBackoff
 	}
+}
 
-	for attempt := range retryCount {
-		sleepTime := r.progression.Duration(attempt)
-		if sleepTime <= 0 {
-			finished = rf()
-			if finished {
-				return true
-			}
+// WithExpectedLatency makes RepeatContext give up before sleeping when the ctx deadline
+// would pass before the next attempt is expected to finish, i.e. the time left is less than
+// the sleep duration plus expectedLatency, zero means no check
+func WithExpectedLatency(expectedLatency time.Duration) Option {
+	return func(r *Repeater) {
+		r.expectedLatency = expectedLatency
+	}
+}
 
-			continue
-		}
+// WithAbortOn makes RepeatErr stop without retries when the repeat func returns an error
+// matching any of errs by errors.Is, like the error was wrapped in Abort,
+// e.g. WithAbortOn(ErrUnauthorized, ErrForbidden) for failures which are never retryable
+func WithAbortOn(errs ...error) Option {
+	return func(r *Repeater) {
+		r.abortOn = append(r.abortOn, errs...)
+	}
+}
 
-		<-time.After(sleepTime)
+// WithGiveUpAfterSame makes RepeatErr stop without further retries when the repeat func returned
+// the same error n times in a row, errors are compared by errors.Is(err, prev) unless same is given.
+// *Error returned by RepeatErr wraps ErrSameError then, zero n means no limit
+func WithGiveUpAfterSame(n uint64, same ...func(prev, err error) bool) Option {
+	return func(r *Repeater) {
+		r.giveUpAfterSame = n
+		r.sameErr = sameError
 
-		finished = rf()
-		if finished {
-			return true
+		if len(same) > 0 {
+			r.sameErr = same[0]
 		}
 	}
+}
 
-	return false
+func sameError(prev, err error) bool {
+	return errors.Is(err, prev)
+}
+
+// WithoutContextCheck makes RepeatContext call the repeat func even if ctx is already done.
+// By default RepeatContext gives up without calling the repeat func when ctx is done
+// before the first attempt or after a failed one, the sleep is interrupted by ctx in both cases
+func WithoutContextCheck() Option {
+	return func(r *Repeater) {
+		r.skipContextCheck = true
+	}
+}
+
+func New(progression DurationProgression, opts ...Option) *Repeater {
+	stats := &statsRecorder{}
+
+	r := &Repeater{
+		progression: progression,
+		recorder:    recorders{stats},
+		stats:       stats,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	r.clocked = r.maxElapsed > 0 || len(r.recorder) > 1 || r.contextInfo
+
+	return r
+}
+
+// Progression returns the progression passed to New
+func (r *Repeater) Progression() DurationProgression {
+	return r.progression
+}
+
+// Repeat is RepeatContext with the background context, both share the same loop
+func (r *Repeater) Repeat(rf RepeatFunc, retryCount uint64) (finished bool) {
+	state := r.start()
+
+	return r.repeatContext(context.Background(), &state, func(context.Context) bool { return rf() }, retryCount)
 }
 
 func (r *Repeater) RepeatContext(ctx context.Context, rfctx RepeatFuncContext, retryCount uint64) (finished bool) {
-	finished = rfctx(ctx)
+	state := r.start()
+
+	return r.repeatContext(ctx, &state, rfctx, retryCount)
+}
+
+func (r *Repeater) repeatContext(ctx context.Context, state *repeatState, rfctx RepeatFuncContext, retryCount uint64) (finished bool) {
+	defer state.timer.stop()
+
+	state.retryCount = retryCount
+
+	if r.contextDone(ctx) || !r.controller.wait(ctx) {
+		state.interrupt(ctx.Err(), false)
+		r.giveUp(ctx, state, state.attempt, false)
+
+		return false
+	}
+
+	finished = r.callContext(ctx, state, rfctx)
 	if finished {
 		return true
 	}
 
-	for attempt := range retryCount {
-		sleepTime := r.progression.Duration(attempt)
-		if sleepTime <= 0 {
-			finished = rfctx(ctx)
-			if finished {
-				return true
+	for state.attempt < retryCount {
+		if state.aborted || r.contextDone(ctx) {
+			if !state.aborted {
+				state.interrupt(ctx.Err(), false)
 			}
 
-			continue
+			r.giveUp(ctx, state, state.attempt, false)
+
+			return false
+		}
+
+		sleepTime := r.sleepTime(ctx, state)
+		if r.elapsedExceeded(state, sleepTime) {
+			r.giveUp(ctx, state, state.attempt, true)
+
+			return false
 		}
 
-		timer := time.NewTimer(sleepTime)
+		if r.totalBackoffExceeded(state, sleepTime) {
+			state.cause = ErrMaxTotalBackoff
+			r.giveUp(ctx, state, state.attempt, true)
+
+			return false
+		}
+
+		if r.deadlineExceeded(ctx, sleepTime) {
+			// the deadline would pass during the sleep
+			state.interrupt(context.DeadlineExceeded, true)
+			r.giveUp(ctx, state, state.attempt, false)
+
+			return false
+		}
 
-		select {
-		case <-ctx.Done():
-			timer.Stop()
+		if !takeBudget(ctx) {
+			state.cause = ErrBudgetExhausted
+			r.giveUp(ctx, state, state.attempt, true)
 
 			return false
-		case <-timer.C:
-			finished = rfctx(ctx)
-			if finished {
-				return true
+		}
+
+		state.attempt++
+		state.slept += max(sleepTime, 0)
+
+		r.recorder.RetryScheduled(ctx, state.attempt, state.elapsed(), sleepTime)
+
+		if sleepTime > 0 && skipSleep(ctx) {
+			state.clock.skipped += sleepTime
+		} else if sleepTime > 0 {
+			select {
+			case <-ctx.Done():
+				state.interrupt(ctx.Err(), true)
+				r.giveUp(ctx, state, state.attempt-1, false)
+
+				return false
+			case <-state.timer.after(sleepTime):
+			case <-r.controller.kickedChan():
+				state.timer.stop()
 			}
 		}
+
+		if !r.controller.wait(ctx) {
+			state.interrupt(ctx.Err(), true)
+			r.giveUp(ctx, state, state.attempt-1, false)
+
+			return false
+		}
+
+		finished = r.callContext(ctx, state, rfctx)
+		if finished {
+			return true
+		}
 	}
 
+	state.retriesExceeded = !state.aborted
+
+	r.giveUp(ctx, state, retryCount, !state.aborted)
+
 	return false
 }
 
+// sleepTime returns the delay before the next retry and resets the overridden delay
+func (r *Repeater) sleepTime(ctx context.Context, state *repeatState) time.Duration {
+	if state.delay.set {
+		sleepTime := state.delay.duration
+		state.delay = Delay{}
+
+		return sleepTime
+	}
+
+	return r.progressionFor(ctx).Duration(state.attempt)
+}
+
+func (r *Repeater) giveUp(ctx context.Context, state *repeatState, attempt uint64, exhausted bool) {
+	state.exhausted = exhausted
+
+	if state.stopped {
+		r.recorder.Stopped(ctx, attempt, state.elapsed())
+
+		return
+	}
+
+	if state.ctxErr != nil {
+		r.recorder.canceled(ctx, attempt, state.elapsed())
+
+		return
+	}
+
+	r.recorder.GaveUp(ctx, attempt, state.elapsed(), exhausted)
+}
+
+func (r *Repeater) contextDone(ctx context.Context) bool {
+	return !r.skipContextCheck && ctx.Err() != nil
+}
+
+func (r *Repeater) callContext(ctx context.Context, state *repeatState, rfctx RepeatFuncContext) (finished bool) {
+	r.recorder.AttemptStarted(ctx, state.attempt, state.elapsed())
+
+	finished = rfctx(r.attemptContext(ctx, state))
+
+	if state.err != nil {
+		r.recorder.AttemptFailed(ctx, state.attempt, state.elapsed(), state.err)
+
+		return finished
+	}
+
+	r.recorder.AttemptFinished(ctx, state.attempt, state.elapsed(), finished)
+
+	return finished
+}
+
+// repeatState is the per call state of the repeat loop,
+// every stop condition reads it instead of keeping its own counters
+type repeatState struct {
+	// attempt is the index of the current or the last finished attempt,
+	// zero is the initial call
+	attempt uint64
+	// retryCount is the retry count of the repeat call
+	retryCount uint64
+	clock      clock
+	timer      sleepTimer
+	// exhausted is set when the repeat gave up because of retry count or max elapsed
+	exhausted bool
+	// aborted is set by the repeat func to stop the repeat without retries
+	aborted bool
+	// stopped is set with aborted when the repeat func returned a Stop error
+	stopped bool
+	// err is the error returned by the current RepeatErr attempt
+	err error
+	// delay is set by the repeat func to override the progression duration before the next retry
+	delay Delay
+	// slept is the sum of sleeps between attempts
+	slept time.Duration
+	// cause is the sentinel error of the stop condition which gave up, if it has one
+	cause error
+	// retriesExceeded is set when the repeat gave up because of retry count,
+	// its cause is created by errCause, so plain repeats don't allocate it
+	retriesExceeded bool
+	// ctxErr is the context error which stopped the repeat
+	ctxErr error
+	// inSleep is set when the context stopped the repeat during the sleep before a retry
+	inSleep bool
+}
+
+// interrupt records the context error which stopped the repeat
+func (s *repeatState) interrupt(err error, inSleep bool) {
+	s.ctxErr, s.inSleep = err, inSleep
+}
+
+// errCause returns the cause of Error of the repeat
+func (s *repeatState) errCause() error {
+	if s.retriesExceeded {
+		return &RetryCountError{Attempts: s.retryCount + 1, MaxAttempts: s.retryCount + 1}
+	}
+
+	return s.cause
+}
+
+func (s *repeatState) elapsed() time.Duration {
+	return s.clock.elapsed()
+}
+
+// start creates the state of a new repeat call
+func (r *Repeater) start() repeatState {
+	return repeatState{clock: r.clock()}
+}
+
+// startClocked creates the state of a new repeat call which reports elapsed time to the caller
+func (r *Repeater) startClocked() repeatState {
+	return repeatState{clock: clock{start: time.Now()}}
+}
+
+// sleepTimer lazily creates one timer per repeat and resets it for every sleep
+type sleepTimer struct {
+	timer *time.Timer
+}
+
+// after must be called only when the previous timer value was received or the timer is stopped
+func (s *sleepTimer) after(d time.Duration) <-chan time.Time {
+	if s.timer == nil {
+		s.timer = time.NewTimer(d)
+	} else {
+		s.timer.Reset(d)
+	}
+
+	return s.timer.C
+}
+
+func (s *sleepTimer) stop() {
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+}
+
+// clock measures repeat elapsed time from a single monotonic start point,
+// so max elapsed and every recorder observe the same time without calling time.Now on their own
+type clock struct {
+	start time.Time
+	// skipped is the sum of sleeps skipped by ContextWithoutSleep
+	skipped time.Duration
+}
+
+// clock starts a repeat clock, time.Now is skipped when nothing needs elapsed time
+func (r *Repeater) clock() clock {
+	if !r.clocked {
+		return clock{}
+	}
+
+	return clock{start: time.Now()}
+}
+
+// elapsed returns zero for the not started clock
+func (c clock) elapsed() time.Duration {
+	if c.start.IsZero() {
+		return 0
+	}
+
+	return time.Since(c.start) + c.skipped
+}
+
+func (r *Repeater) elapsedExceeded(state *repeatState, sleepTime time.Duration) bool {
+	if r.maxElapsed <= 0 {
+		return false
+	}
+
+	return state.elapsed()+max(sleepTime, 0) > r.maxElapsed
+}
+
+func (r *Repeater) totalBackoffExceeded(state *repeatState, sleepTime time.Duration) bool {
+	if r.maxTotalBackoff <= 0 {
+		return false
+	}
+
+	return state.slept+max(sleepTime, 0) > r.maxTotalBackoff
+}
+
+// deadlineExceeded reports whether the next attempt is not expected to finish before the ctx deadline
+func (r *Repeater) deadlineExceeded(ctx context.Context, sleepTime time.Duration) bool {
+	if r.expectedLatency <= 0 {
+		return false
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return false
+	}
+
+	return time.Until(deadline) < max(sleepTime, 0)+r.expectedLatency
+}
+
 type ArifmeticProggression struct {
 	initial time.Duration
 	delta   time.Duration
@@ -127,10 +474,17 @@ func (p ConstantProgression) Duration(uint64) time.Duration {
 
 type FibonacciProgression time.Duration
 
+// Duration truncates overflowed durations to the max time.Duration
 func (s FibonacciProgression) Duration(attempt uint64) time.Duration {
-	return time.Duration(s) * time.Duration(fibonacciIterative(attempt+1))
+	fib := fibonacciIterative(attempt + 1)
+	if s > 0 && fib > uint64(math.MaxInt64/s) {
+		return math.MaxInt64
+	}
+
+	return time.Duration(s) * time.Duration(fib)
 }
 
+// fibonacciIterative truncates overflowed numbers to math.MaxUint64
 func fibonacciIterative(n uint64) uint64 {
 	if n <= 1 {
 		return n
@@ -138,8 +492,72 @@ func fibonacciIterative(n uint64) uint64 {
 
 	var n2, n1 uint64 = 0, 1
 	for i := uint64(2); i <= n; i++ {
+		if n1 > math.MaxUint64-n2 {
+			return math.MaxUint64
+		}
+
 		n2, n1 = n1, n1+n2
 	}
 
 	return n1
 }
+
+type ExponentialProgression struct {
+	initial time.Duration
+	factor  float64
+}
+
+// NewExponentialProgression returns progression with initial * factor^attempt durations,
+// overflowed durations are truncated to the max time.Duration
+func NewExponentialProgression(initial time.Duration, factor float64) ExponentialProgression {
+	return ExponentialProgression{initial: initial, factor: factor}
+}
+
+func (e ExponentialProgression) Duration(attempt uint64) time.Duration {
+	d := float64(e.initial) * math.Pow(e.factor, float64(attempt))
+	if d >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return time.Duration(d)
+}
+
+type CappedProgression struct {
+	progression DurationProgression
+	limit       time.Duration
+}
+
+// NewCappedProgression limits durations of progression by limit
+func NewCappedProgression(progression DurationProgression, limit time.Duration) CappedProgression {
+	return CappedProgression{progression: progression, limit: limit}
+}
+
+func (c CappedProgression) Duration(attempt uint64) time.Duration {
+	return min(c.progression.Duration(attempt), c.limit)
+}
+
+type JitterProgression struct {
+	progression DurationProgression
+	factor      float64
+}
+
+// NewJitterProgression randomizes durations of progression in [d - d*factor, d + d*factor] range,
+// factor is expected to be in [0, 1] range, durations are truncated to [0, max time.Duration]
+func NewJitterProgression(progression DurationProgression, factor float64) JitterProgression {
+	return JitterProgression{progression: progression, factor: factor}
+}
+
+func (j JitterProgression) Duration(attempt uint64) time.Duration {
+	d := float64(j.progression.Duration(attempt))
+
+	d += d * j.factor * (rand.Float64()*2 - 1)
+
+	switch {
+	case d >= math.MaxInt64:
+		return math.MaxInt64
+	case d <= 0:
+		return 0
+	}
+
+	return time.Duration(d)
+}
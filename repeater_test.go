@@ -382,7 +382,7 @@ type RepeatOperation struct {
 
 func (r RepeatOperation) Execute() func() bool {
 	return func() bool {
-		<-time.After(r.Duration)
+		time.Sleep(r.Duration)
 
 		return r.OK
 	}
@@ -390,11 +390,37 @@ func (r RepeatOperation) Execute() func() bool {
 
 func (r RepeatOperation) ExecuteContext() func(context.Context) bool {
 	return func(ctx context.Context) bool {
+		timer := time.NewTimer(r.Duration)
+		defer timer.Stop()
+
 		select {
-		case <-time.After(r.Duration):
+		case <-timer.C:
 			return r.OK
 		case <-ctx.Done():
 			return false
 		}
 	}
 }
+
+func Test_Repeater_TimerReused(t *testing.T) {
+	rp := repeater.New(repeater.ConstantProgression(time.Nanosecond))
+
+	rf := func() bool { return false }
+
+	oneRetry := testing.AllocsPerRun(100, func() { rp.Repeat(rf, 1) })
+	tenRetries := testing.AllocsPerRun(100, func() { rp.Repeat(rf, 10) })
+
+	if oneRetry != tenRetries {
+		t.Fatalf("allocations grow with retry count, one retry %v, ten retries %v", oneRetry, tenRetries)
+	}
+
+	ctx := context.Background()
+	rfctx := func(context.Context) bool { return false }
+
+	oneRetry = testing.AllocsPerRun(100, func() { rp.RepeatContext(ctx, rfctx, 1) })
+	tenRetries = testing.AllocsPerRun(100, func() { rp.RepeatContext(ctx, rfctx, 10) })
+
+	if oneRetry != tenRetries {
+		t.Fatalf("context allocations grow with retry count, one retry %v, ten retries %v", oneRetry, tenRetries)
+	}
+}
@@ -0,0 +1,62 @@
+package repeatertest
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// BackoffSpy is a repeater.DurationProgression returning scripted durations,
+// it records requested attempts and fails the test when they are not 0, 1, 2, ...
+type BackoffSpy struct {
+	t         testing.TB
+	durations []time.Duration
+
+	mu       sync.Mutex
+	attempts []uint64
+}
+
+// NewBackoffSpy returns BackoffSpy returning durations by attempt,
+// the last duration is repeated for attempts beyond durations, zero is returned without durations
+func NewBackoffSpy(t testing.TB, durations ...time.Duration) *BackoffSpy {
+	return &BackoffSpy{
+		t:         t,
+		durations: durations,
+	}
+}
+
+func (b *BackoffSpy) Duration(attempt uint64) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	expected := uint64(len(b.attempts))
+	if attempt != expected {
+		b.t.Errorf("backoff spy: expected attempt %d, actual %d, previous attempts %v", expected, attempt, b.attempts)
+	}
+
+	b.attempts = append(b.attempts, attempt)
+
+	switch {
+	case len(b.durations) == 0:
+		return 0
+	case attempt < uint64(len(b.durations)):
+		return b.durations[attempt]
+	default:
+		return b.durations[len(b.durations)-1]
+	}
+}
+
+// Attempts returns a copy of requested attempts
+func (b *BackoffSpy) Attempts() []uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]uint64(nil), b.attempts...)
+}
+
+// Reset forgets requested attempts, so the spy may be used by the next repeat
+func (b *BackoffSpy) Reset() {
+	b.mu.Lock()
+	b.attempts = nil
+	b.mu.Unlock()
+}
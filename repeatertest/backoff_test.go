@@ -0,0 +1,64 @@
+package repeatertest_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	"github.com/amidgo/repeater/repeatertest"
+)
+
+func Test_BackoffSpy(t *testing.T) {
+	t.Parallel()
+
+	spy := repeatertest.NewBackoffSpy(t, time.Millisecond, 0)
+	recorder := &repeatertest.Recorder{}
+
+	rp := repeater.New(spy, repeater.WithRecorder(recorder))
+
+	rp.Repeat(func() bool { return false }, 3)
+
+	if !slices.Equal([]uint64{0, 1, 2}, spy.Attempts()) {
+		t.Fatalf("wrong attempts %v", spy.Attempts())
+	}
+
+	expectedDelays := []time.Duration{time.Millisecond, 0, 0}
+
+	if !slices.Equal(expectedDelays, recorder.Delays()) {
+		t.Fatalf("wrong delays, expected %v, actual %v", expectedDelays, recorder.Delays())
+	}
+
+	spy.Reset()
+
+	rp.Repeat(func() bool { return false }, 1)
+
+	if !slices.Equal([]uint64{0}, spy.Attempts()) {
+		t.Fatalf("wrong attempts after reset %v", spy.Attempts())
+	}
+}
+
+type errorfRecorder struct {
+	testing.TB
+	errors int
+}
+
+func (e *errorfRecorder) Errorf(string, ...any) {
+	e.errors++
+}
+
+func Test_BackoffSpy_NotSequential(t *testing.T) {
+	t.Parallel()
+
+	tb := &errorfRecorder{TB: t}
+
+	spy := repeatertest.NewBackoffSpy(tb)
+
+	spy.Duration(0)
+	spy.Duration(2)
+	spy.Duration(2)
+
+	if tb.errors != 1 {
+		t.Fatalf("expected 1 reported error, actual %d", tb.errors)
+	}
+}
@@ -0,0 +1,89 @@
+package repeatertest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrorsDiff lists differences between an actual error tree and expected errors
+type ErrorsDiff struct {
+	// expected errors not matched by errors.Is
+	Missing []error
+	// leaf errors of the actual error not matching any expected error
+	Unexpected []error
+}
+
+func (d ErrorsDiff) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Unexpected) == 0
+}
+
+func (d ErrorsDiff) String() string {
+	var b strings.Builder
+
+	for _, err := range d.Missing {
+		fmt.Fprintf(&b, "missing: %v\n", err)
+	}
+
+	for _, err := range d.Unexpected {
+		fmt.Fprintf(&b, "unexpected: %v\n", err)
+	}
+
+	return b.String()
+}
+
+// DiffErrors compares joined and wrapped errors of actual with expected ones,
+// leaves of the actual tree are errors which don't wrap other errors
+func DiffErrors(actual error, expected ...error) ErrorsDiff {
+	var diff ErrorsDiff
+
+	for _, err := range expected {
+		if !errors.Is(actual, err) {
+			diff.Missing = append(diff.Missing, err)
+		}
+	}
+
+	for _, leaf := range leafErrors(actual) {
+		if !matchesAny(leaf, expected) {
+			diff.Unexpected = append(diff.Unexpected, leaf)
+		}
+	}
+
+	return diff
+}
+
+func matchesAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func leafErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	switch wrapper := err.(type) {
+	case interface{ Unwrap() []error }:
+		var leaves []error
+
+		for _, err := range wrapper.Unwrap() {
+			leaves = append(leaves, leafErrors(err)...)
+		}
+
+		return leaves
+	case interface{ Unwrap() error }:
+		inner := wrapper.Unwrap()
+		if inner == nil {
+			return []error{err}
+		}
+
+		return leafErrors(inner)
+	default:
+		return []error{err}
+	}
+}
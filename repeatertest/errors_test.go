@@ -0,0 +1,44 @@
+package repeatertest_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/amidgo/repeater"
+	"github.com/amidgo/repeater/repeatertest"
+)
+
+func Test_DiffErrors(t *testing.T) {
+	t.Parallel()
+
+	actual := errors.Join(
+		&repeater.Error{Attempts: 3, Last: fmt.Errorf("read body: %w", io.ErrUnexpectedEOF), Exceeded: true},
+		io.ErrClosedPipe,
+	)
+
+	diff := repeatertest.DiffErrors(actual, io.ErrUnexpectedEOF, io.EOF)
+	if diff.Empty() {
+		t.Fatal("expected not empty diff")
+	}
+
+	if !slices.Equal([]error{io.EOF}, diff.Missing) {
+		t.Fatalf("wrong missing errors %v", diff.Missing)
+	}
+
+	if !slices.Equal([]error{io.ErrClosedPipe}, diff.Unexpected) {
+		t.Fatalf("wrong unexpected errors %v", diff.Unexpected)
+	}
+
+	expected := "missing: EOF\nunexpected: io: read/write on closed pipe\n"
+	if diff.String() != expected {
+		t.Fatalf("wrong diff string, expected %q, actual %q", expected, diff.String())
+	}
+
+	diff = repeatertest.DiffErrors(actual, io.ErrUnexpectedEOF, io.ErrClosedPipe)
+	if !diff.Empty() {
+		t.Fatalf("expected empty diff, actual\n%s", diff)
+	}
+}
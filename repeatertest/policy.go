@@ -0,0 +1,116 @@
+package repeatertest
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/amidgo/repeater"
+)
+
+// maxCheckedAttempts limits progression durations and attempts checked by CheckPolicy for huge retry counts
+const maxCheckedAttempts = 1 << 16
+
+var errAttemptFailed = errors.New("repeatertest: attempt failed")
+
+// CheckPolicy asserts policy invariants: progression durations are not negative for every retry,
+// a repeat of the policy makes no more than RetryCount retries, finishes on the first true result
+// and otherwise returns *repeater.Error wrapping the last attempt error, which gave up because of a limit
+// or an abort and wraps repeater.ErrRetryCountExceeded when every retry was used. Missing results are false.
+// The policy runs with its own options but without sleeping, see repeater.ContextWithoutSleep,
+// repeats longer than 65536 attempts are canceled, so CheckPolicy may be called from fuzz tests with generated results
+func CheckPolicy(t testing.TB, policy repeater.Policy, results []bool) {
+	t.Helper()
+
+	progression := policy.Repeater().Progression()
+	retryCount := policy.RetryCount()
+
+	for attempt := range min(retryCount, maxCheckedAttempts) {
+		d := progression.Duration(attempt)
+		if d < 0 {
+			t.Errorf("negative duration %s of attempt %d", d, attempt)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(repeater.ContextWithoutSleep(context.Background()))
+	defer cancel()
+
+	var calls uint64
+
+	err := policy.RepeatErr(ctx, func(context.Context) error {
+		calls++
+
+		if calls == maxCheckedAttempts {
+			cancel()
+		}
+
+		if calls <= uint64(len(results)) && results[calls-1] {
+			return nil
+		}
+
+		return errAttemptFailed
+	})
+
+	// retries is the number of calls after the initial one, it can't overflow unlike retryCount+1
+	retries := calls - 1
+
+	if retries > retryCount {
+		t.Errorf("%d calls exceed retry count %d", calls, retryCount)
+	}
+
+	// firstFinished is the call which returns the first true result
+	firstFinished := uint64(math.MaxUint64)
+
+	for i, result := range results {
+		if result {
+			firstFinished = uint64(i) + 1
+
+			break
+		}
+	}
+
+	if err == nil {
+		if calls != firstFinished {
+			t.Errorf("repeat finished after %d calls, first true result is at call %d", calls, firstFinished)
+		}
+
+		return
+	}
+
+	if calls > firstFinished {
+		t.Errorf("repeat didn't finish on the true result of call %d, made %d calls", firstFinished, calls)
+	}
+
+	repeatErr := &repeater.Error{}
+	if !errors.As(err, &repeatErr) {
+		t.Errorf("repeat returned %v, expected *repeater.Error", err)
+
+		return
+	}
+
+	if repeatErr.Attempts != calls {
+		t.Errorf("error has %d attempts, actual calls %d", repeatErr.Attempts, calls)
+	}
+
+	if calls > 0 && !errors.Is(err, errAttemptFailed) {
+		t.Errorf("error %v doesn't wrap the last attempt error", err)
+	}
+
+	if calls >= maxCheckedAttempts && repeatErr.Canceled() {
+		return
+	}
+
+	if repeatErr.Context != nil || (!repeatErr.Exceeded && !repeatErr.Aborted) {
+		t.Errorf("repeat gave up without a limit or an abort: %v", err)
+	}
+
+	retriesExceeded := errors.Is(err, repeater.ErrRetryCountExceeded)
+
+	switch {
+	case retries == retryCount && !repeatErr.Aborted && !retriesExceeded:
+		t.Errorf("repeat used every retry, error %v doesn't wrap repeater.ErrRetryCountExceeded", err)
+	case retries < retryCount && retriesExceeded:
+		t.Errorf("repeat made %d of %d retries, error %v wraps repeater.ErrRetryCountExceeded", retries, retryCount, err)
+	}
+}
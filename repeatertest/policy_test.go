@@ -0,0 +1,98 @@
+package repeatertest_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	"github.com/amidgo/repeater/repeatertest"
+)
+
+func Test_CheckPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := repeater.NewPolicy(
+		repeater.WithProgression(repeater.NewExponentialProgression(time.Second, 2)),
+		repeater.WithMaxRetries(3),
+	)
+
+	for _, results := range [][]bool{
+		nil,
+		{true},
+		{false, false, true},
+		{false, false, false, false, true},
+		{false, false, false, false, false, false},
+	} {
+		repeatertest.CheckPolicy(t, policy, results)
+	}
+}
+
+func Test_CheckPolicy_NegativeDuration(t *testing.T) {
+	t.Parallel()
+
+	tb := &errorfRecorder{TB: t}
+
+	policy := repeater.NewPolicy(
+		repeater.WithProgression(repeater.NewArifmeticProgression(time.Second, -time.Second)),
+		repeater.WithMaxRetries(3),
+	)
+
+	repeatertest.CheckPolicy(tb, policy, nil)
+
+	if tb.errors != 1 {
+		t.Fatalf("expected 1 reported error, actual %d", tb.errors)
+	}
+}
+
+func Test_CheckPolicy_Options(t *testing.T) {
+	t.Parallel()
+
+	tb := &errorfRecorder{TB: t}
+
+	for _, policy := range []repeater.Policy{
+		repeater.NewPolicy(
+			repeater.WithProgression(repeater.ConstantProgression(time.Hour)),
+			repeater.WithMaxRetries(math.MaxUint64),
+		),
+		repeater.NewPolicy(
+			repeater.WithProgression(repeater.ConstantProgression(time.Hour)),
+			repeater.WithMaxRetries(10),
+			repeater.WithRepeaterOptions(repeater.WithMaxElapsed(time.Hour*3)),
+		),
+		repeater.NewPolicy(
+			repeater.WithProgression(repeater.ConstantProgression(time.Hour)),
+			repeater.WithMaxRetries(10),
+			repeater.WithRepeaterOptions(repeater.WithMaxTotalBackoff(time.Hour*2)),
+		),
+		repeater.NewPolicy(
+			repeater.WithProgression(repeater.NewJitterProgression(repeater.ConstantProgression(time.Hour), 0.5)),
+			repeater.WithMaxRetries(10),
+			repeater.WithRepeaterOptions(repeater.WithGiveUpAfterSame(3)),
+		),
+	} {
+		repeatertest.CheckPolicy(tb, policy, []bool{false, false})
+	}
+
+	if tb.errors != 0 {
+		t.Fatalf("expected no reported errors, actual %d", tb.errors)
+	}
+}
+
+func Fuzz_CheckPolicy(f *testing.F) {
+	f.Add(uint8(3), []byte{0, 0, 1})
+
+	f.Fuzz(func(t *testing.T, retryCount uint8, data []byte) {
+		results := make([]bool, len(data))
+		for i, b := range data {
+			results[i] = b%2 == 1
+		}
+
+		policy := repeater.NewPolicy(
+			repeater.WithProgression(repeater.FibonacciProgression(time.Millisecond)),
+			repeater.WithMaxRetries(uint64(retryCount)),
+		)
+
+		repeatertest.CheckPolicy(t, policy, results)
+	})
+}
@@ -0,0 +1,88 @@
+package repeatertest
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Attempt is a finished attempt captured by Recorder
+type Attempt struct {
+	// zero is the initial attempt, retries start from 1
+	Number   uint64
+	Finished bool
+}
+
+// GiveUp is a repeat which stopped without success captured by Recorder
+type GiveUp struct {
+	Attempt   uint64
+	Exhausted bool
+}
+
+// Recorder is a repeater.Recorder and repeater.StopRecorder capturing finished attempts,
+// scheduled delays, give ups and stops for assertions, it is safe to share between concurrent repeats
+type Recorder struct {
+	mu       sync.Mutex
+	attempts []Attempt
+	delays   []time.Duration
+	giveUps  []GiveUp
+	stops    []uint64
+}
+
+func (r *Recorder) AttemptStarted(context.Context, uint64, time.Duration) {}
+
+func (r *Recorder) AttemptFinished(_ context.Context, attempt uint64, _ time.Duration, finished bool) {
+	r.mu.Lock()
+	r.attempts = append(r.attempts, Attempt{Number: attempt, Finished: finished})
+	r.mu.Unlock()
+}
+
+func (r *Recorder) RetryScheduled(_ context.Context, _ uint64, _, delay time.Duration) {
+	r.mu.Lock()
+	r.delays = append(r.delays, delay)
+	r.mu.Unlock()
+}
+
+func (r *Recorder) GaveUp(_ context.Context, attempt uint64, _ time.Duration, exhausted bool) {
+	r.mu.Lock()
+	r.giveUps = append(r.giveUps, GiveUp{Attempt: attempt, Exhausted: exhausted})
+	r.mu.Unlock()
+}
+
+func (r *Recorder) Stopped(_ context.Context, attempt uint64, _ time.Duration) {
+	r.mu.Lock()
+	r.stops = append(r.stops, attempt)
+	r.mu.Unlock()
+}
+
+// Attempts returns a copy of finished attempts in the order they finished
+func (r *Recorder) Attempts() []Attempt {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Attempt(nil), r.attempts...)
+}
+
+// Delays returns a copy of scheduled retry delays
+func (r *Recorder) Delays() []time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]time.Duration(nil), r.delays...)
+}
+
+// GiveUps returns a copy of captured give ups
+func (r *Recorder) GiveUps() []GiveUp {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]GiveUp(nil), r.giveUps...)
+}
+
+// Stops returns a copy of the last attempts of repeats stopped by a Stop error
+func (r *Recorder) Stops() []uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]uint64(nil), r.stops...)
+}
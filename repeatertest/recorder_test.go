@@ -0,0 +1,55 @@
+package repeatertest_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	"github.com/amidgo/repeater/repeatertest"
+)
+
+func Test_Recorder(t *testing.T) {
+	t.Parallel()
+
+	recorder := &repeatertest.Recorder{}
+
+	rp := repeater.New(
+		repeater.NewArifmeticProgression(0, time.Millisecond),
+		repeater.WithRecorder(recorder),
+	)
+
+	calls := 0
+
+	rp.Repeat(func() bool {
+		calls++
+
+		return calls == 3
+	}, 3)
+
+	rp.Repeat(func() bool { return false }, 1)
+
+	expectedAttempts := []repeatertest.Attempt{
+		{Number: 0, Finished: false},
+		{Number: 1, Finished: false},
+		{Number: 2, Finished: true},
+		{Number: 0, Finished: false},
+		{Number: 1, Finished: false},
+	}
+
+	if !slices.Equal(expectedAttempts, recorder.Attempts()) {
+		t.Fatalf("wrong attempts, expected %v, actual %v", expectedAttempts, recorder.Attempts())
+	}
+
+	expectedDelays := []time.Duration{0, time.Millisecond, 0}
+
+	if !slices.Equal(expectedDelays, recorder.Delays()) {
+		t.Fatalf("wrong delays, expected %v, actual %v", expectedDelays, recorder.Delays())
+	}
+
+	expectedGiveUps := []repeatertest.GiveUp{{Attempt: 1, Exhausted: true}}
+
+	if !slices.Equal(expectedGiveUps, recorder.GiveUps()) {
+		t.Fatalf("wrong give ups, expected %v, actual %v", expectedGiveUps, recorder.GiveUps())
+	}
+}
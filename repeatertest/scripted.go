@@ -0,0 +1,117 @@
+package repeatertest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+// ErrStepFailed is returned by ScriptedFunc.ErrFunc for failed steps without Err
+var ErrStepFailed = errors.New("scripted step failed")
+
+// Step is a single scripted call
+type Step struct {
+	// simulated call duration, calls with context return early when ctx is done
+	Duration time.Duration
+	OK       bool
+	// error returned by ErrFunc for a failed step, ErrStepFailed is used when nil
+	Err error
+}
+
+// ScriptedFunc replays steps one per call, it panics when called more times than there are steps
+type ScriptedFunc struct {
+	mu    sync.Mutex
+	steps []Step
+	calls int
+}
+
+func NewScriptedFunc(steps ...Step) *ScriptedFunc {
+	return &ScriptedFunc{steps: steps}
+}
+
+// Calls returns the number of replayed steps
+func (s *ScriptedFunc) Calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls
+}
+
+// Consumed reports whether every step was replayed
+func (s *ScriptedFunc) Consumed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls == len(s.steps)
+}
+
+func (s *ScriptedFunc) Func() repeater.RepeatFunc {
+	return func() bool {
+		step := s.next()
+
+		time.Sleep(step.Duration)
+
+		return step.OK
+	}
+}
+
+// FuncContext returns false without waiting the rest of the step duration when ctx is done
+func (s *ScriptedFunc) FuncContext() repeater.RepeatFuncContext {
+	return func(ctx context.Context) bool {
+		step := s.next()
+
+		return wait(ctx, step.Duration) == nil && step.OK
+	}
+}
+
+// ErrFunc returns ctx error without waiting the rest of the step duration when ctx is done
+func (s *ScriptedFunc) ErrFunc() repeater.RepeatErrFunc {
+	return func(ctx context.Context) error {
+		step := s.next()
+
+		err := wait(ctx, step.Duration)
+		switch {
+		case err != nil:
+			return err
+		case step.OK:
+			return nil
+		case step.Err != nil:
+			return step.Err
+		default:
+			return ErrStepFailed
+		}
+	}
+}
+
+func (s *ScriptedFunc) next() Step {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.calls == len(s.steps) {
+		panic("repeatertest: scripted func called more times than there are steps")
+	}
+
+	step := s.steps[s.calls]
+	s.calls++
+
+	return step
+}
+
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,74 @@
+package repeatertest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	"github.com/amidgo/repeater/repeatertest"
+)
+
+func Test_ScriptedFunc(t *testing.T) {
+	t.Parallel()
+
+	t.Run("func", func(t *testing.T) {
+		t.Parallel()
+
+		scripted := repeatertest.NewScriptedFunc(
+			repeatertest.Step{OK: false},
+			repeatertest.Step{Duration: time.Millisecond, OK: true},
+		)
+
+		finished := repeater.Repeat(repeater.ConstantProgression(0), scripted.Func(), 3)
+		if !finished {
+			t.Fatal("expected finished repeat")
+		}
+
+		if !scripted.Consumed() || scripted.Calls() != 2 {
+			t.Fatalf("expected 2 consumed steps, actual %d calls", scripted.Calls())
+		}
+	})
+
+	t.Run("func context canceled", func(t *testing.T) {
+		t.Parallel()
+
+		scripted := repeatertest.NewScriptedFunc(
+			repeatertest.Step{Duration: time.Second, OK: true},
+		)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cancel()
+
+		start := time.Now()
+
+		finished := repeater.RepeatContext(ctx, repeater.ConstantProgression(0), scripted.FuncContext(), 3)
+		if finished {
+			t.Fatal("expected not finished repeat")
+		}
+
+		if elapsed := time.Since(start); elapsed > time.Millisecond*500 {
+			t.Fatalf("step is not interrupted by context, elapsed %s", elapsed)
+		}
+	})
+
+	t.Run("err func", func(t *testing.T) {
+		t.Parallel()
+
+		scripted := repeatertest.NewScriptedFunc(
+			repeatertest.Step{Err: io.ErrUnexpectedEOF},
+			repeatertest.Step{},
+		)
+
+		err := repeater.RepeatErr(context.Background(), repeater.ConstantProgression(0), scripted.ErrFunc(), 1)
+		if !errors.Is(err, repeatertest.ErrStepFailed) {
+			t.Fatalf("expected ErrStepFailed, actual %v", err)
+		}
+
+		if !scripted.Consumed() {
+			t.Fatal("expected consumed steps")
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package repeatertest
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/amidgo/repeater"
+)
+
+// Stress repeats from goroutines concurrently with the shared policy, the repeat func of the i-th goroutine
+// fails i % (RetryCount+2) times before success, so some repeats exceed the retry count.
+// It asserts results and number of calls of every repeat, that no func is called after success,
+// and that policy stats grew by the expected numbers, so the policy must not be used elsewhere meanwhile.
+// Sleeps of the policy are real, use short durations
+func Stress(t testing.TB, policy repeater.Policy, goroutines int) {
+	t.Helper()
+
+	retryCount := policy.RetryCount()
+	before := policy.Repeater().Stats()
+
+	var (
+		wg                sync.WaitGroup
+		expectedAttempts  atomic.Uint64
+		expectedSuccesses atomic.Uint64
+	)
+
+	for i := range goroutines {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			failures := uint64(i) % (retryCount + 2)
+
+			var (
+				calls uint64
+				done  bool
+			)
+
+			finished := policy.RepeatContext(context.Background(), func(context.Context) bool {
+				if done {
+					t.Errorf("goroutine %d: repeat func called after success", i)
+				}
+
+				calls++
+				done = calls > failures
+
+				return done
+			})
+
+			expectedFinished := failures <= retryCount
+			expectedCalls := min(failures+1, retryCount+1)
+
+			if finished != expectedFinished {
+				t.Errorf("goroutine %d: expected finished %t, actual %t", i, expectedFinished, finished)
+			}
+
+			if calls != expectedCalls {
+				t.Errorf("goroutine %d: expected %d calls, actual %d", i, expectedCalls, calls)
+			}
+
+			expectedAttempts.Add(expectedCalls)
+
+			if expectedFinished {
+				expectedSuccesses.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	after := policy.Repeater().Stats()
+
+	if calls := after.Calls - before.Calls; calls != uint64(goroutines) {
+		t.Errorf("stats: expected %d calls, actual %d", goroutines, calls)
+	}
+
+	if attempts := after.Attempts - before.Attempts; attempts != expectedAttempts.Load() {
+		t.Errorf("stats: expected %d attempts, actual %d", expectedAttempts.Load(), attempts)
+	}
+
+	if successes := after.Successes - before.Successes; successes != expectedSuccesses.Load() {
+		t.Errorf("stats: expected %d successes, actual %d", expectedSuccesses.Load(), successes)
+	}
+}
@@ -0,0 +1,24 @@
+package repeatertest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	"github.com/amidgo/repeater/repeatertest"
+)
+
+func Test_Stress(t *testing.T) {
+	t.Parallel()
+
+	policy := repeater.NewPolicy(
+		repeater.WithProgression(repeater.ConstantProgression(time.Microsecond)),
+		repeater.WithMaxRetries(3),
+		repeater.WithRepeaterOptions(
+			repeater.WithTimeline(),
+			repeater.WithRecorder(&repeatertest.Recorder{}),
+		),
+	)
+
+	repeatertest.Stress(t, policy, 64)
+}
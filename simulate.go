@@ -0,0 +1,160 @@
+package repeater
+
+import (
+	"math"
+	"time"
+)
+
+type StopReason uint8
+
+const (
+	// StopFinished means the repeat func succeeded
+	StopFinished StopReason = iota + 1
+	// StopRetriesExceeded means the retry count was exhausted
+	StopRetriesExceeded
+	// StopMaxElapsed means the next sleep would exceed the max elapsed time
+	StopMaxElapsed
+	// StopMaxTotalBackoff means the next sleep would exceed the max total backoff
+	StopMaxTotalBackoff
+	// StopAborted means the repeat func aborted the repeat
+	StopAborted
+	// StopNoResults means the simulation ran out of results before the repeat stopped
+	StopNoResults
+)
+
+func (s StopReason) String() string {
+	switch s {
+	case StopFinished:
+		return "finished"
+	case StopRetriesExceeded:
+		return "retries exceeded"
+	case StopMaxElapsed:
+		return "max elapsed exceeded"
+	case StopMaxTotalBackoff:
+		return "max total backoff exceeded"
+	case StopAborted:
+		return "aborted"
+	case StopNoResults:
+		return "no results"
+	default:
+		return "unknown"
+	}
+}
+
+// Result is a simulated result of a repeat func call
+type Result uint8
+
+const (
+	// ResultFailed is a failed call which is retried
+	ResultFailed Result = iota
+	// ResultSucceeded is a successful call which finishes the repeat
+	ResultSucceeded
+	// ResultAborted is a failed call which stops the repeat without retries, like an Abort error
+	ResultAborted
+)
+
+// Schedule is a simulated repeat
+type Schedule struct {
+	// number of calls, including the initial one
+	Attempts uint64
+	// sleeps before every retry
+	Delays []time.Duration
+	// sum of positive delays, saturated at the max duration
+	TotalSleep time.Duration
+	StopReason StopReason
+}
+
+// Simulate returns the schedule of policy repeat with results of repeat func calls without calling or sleeping,
+// calls are assumed to take no time. The schedule has no more attempts than results,
+// a repeat which would retry after the last result stops with StopNoResults.
+// Jittered progressions produce a different schedule on every call
+func Simulate(policy Policy, results []Result) Schedule {
+	var schedule Schedule
+
+	rp := policy.repeater
+
+	for attempt, result := range results {
+		schedule.Attempts++
+
+		switch result {
+		case ResultSucceeded:
+			schedule.StopReason = StopFinished
+
+			return schedule
+		case ResultAborted:
+			schedule.StopReason = StopAborted
+
+			return schedule
+		}
+
+		if uint64(attempt) >= policy.retryCount {
+			schedule.StopReason = StopRetriesExceeded
+
+			return schedule
+		}
+
+		sleepTime := rp.progression.Duration(uint64(attempt))
+		if rp.maxElapsed > 0 && schedule.TotalSleep+max(sleepTime, 0) > rp.maxElapsed {
+			schedule.StopReason = StopMaxElapsed
+
+			return schedule
+		}
+
+		if rp.maxTotalBackoff > 0 && schedule.TotalSleep+max(sleepTime, 0) > rp.maxTotalBackoff {
+			schedule.StopReason = StopMaxTotalBackoff
+
+			return schedule
+		}
+
+		if attempt == len(results)-1 {
+			break
+		}
+
+		schedule.Delays = append(schedule.Delays, sleepTime)
+		schedule.TotalSleep = addDelay(schedule.TotalSleep, sleepTime)
+	}
+
+	schedule.StopReason = StopNoResults
+
+	return schedule
+}
+
+// Preview returns the delays of the first n retries of progression, as the repeat loop sleeps them.
+// Jittered progressions produce a different preview on every call
+func Preview(progression DurationProgression, n int) []time.Duration {
+	delays := make([]time.Duration, max(n, 0))
+
+	for i := range delays {
+		delays[i] = progression.Duration(uint64(i))
+	}
+
+	return delays
+}
+
+// PreviewTotal is Preview with the sum of the delays until every retry,
+// the last element is the minimal time n retries take, sums are saturated at the max duration
+func PreviewTotal(progression DurationProgression, n int) []time.Duration {
+	totals := Preview(progression, n)
+
+	var total time.Duration
+
+	for i, delay := range totals {
+		total = addDelay(total, delay)
+		totals[i] = total
+	}
+
+	return totals
+}
+
+// addDelay adds positive delay to total, the sum is saturated at the max duration
+func addDelay(total, delay time.Duration) time.Duration {
+	if delay <= 0 {
+		return total
+	}
+
+	if total > math.MaxInt64-delay {
+		return math.MaxInt64
+	}
+
+	return total + delay
+}
@@ -0,0 +1,120 @@
+package repeater_test
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Simulate(t *testing.T) {
+	t.Parallel()
+
+	policy := repeater.NewPolicy(
+		repeater.WithProgression(repeater.NewExponentialProgression(time.Second, 2)),
+		repeater.WithMaxRetries(4),
+	)
+
+	schedule := repeater.Simulate(policy, []repeater.Result{repeater.ResultFailed, repeater.ResultFailed, repeater.ResultSucceeded})
+
+	if schedule.StopReason != repeater.StopFinished || schedule.Attempts != 3 {
+		t.Fatalf("unexpected schedule %+v", schedule)
+	}
+
+	if !slices.Equal([]time.Duration{time.Second, time.Second * 2}, schedule.Delays) {
+		t.Fatalf("wrong delays %v", schedule.Delays)
+	}
+
+	failures := make([]repeater.Result, 10)
+
+	schedule = repeater.Simulate(policy, failures)
+
+	if schedule.StopReason != repeater.StopRetriesExceeded || schedule.Attempts != 5 || schedule.TotalSleep != time.Second*15 {
+		t.Fatalf("unexpected schedule %+v", schedule)
+	}
+
+	policy = repeater.NewPolicy(
+		repeater.WithProgression(repeater.NewExponentialProgression(time.Second, 2)),
+		repeater.WithMaxRetries(4),
+		repeater.WithRepeaterOptions(repeater.WithMaxElapsed(time.Second*5)),
+	)
+
+	schedule = repeater.Simulate(policy, failures)
+
+	if schedule.StopReason != repeater.StopMaxElapsed || schedule.Attempts != 3 || schedule.TotalSleep != time.Second*3 {
+		t.Fatalf("unexpected schedule %+v", schedule)
+	}
+
+	if schedule.StopReason.String() != "max elapsed exceeded" {
+		t.Fatalf("wrong stop reason string %q", schedule.StopReason)
+	}
+
+	policy = repeater.NewPolicy(
+		repeater.WithProgression(repeater.NewExponentialProgression(time.Second, 2)),
+		repeater.WithMaxRetries(4),
+		repeater.WithRepeaterOptions(repeater.WithMaxTotalBackoff(time.Second*7)),
+	)
+
+	schedule = repeater.Simulate(policy, failures)
+
+	if schedule.StopReason != repeater.StopMaxTotalBackoff || schedule.Attempts != 4 || schedule.TotalSleep != time.Second*7 {
+		t.Fatalf("unexpected schedule %+v", schedule)
+	}
+}
+
+func Test_Simulate_Results(t *testing.T) {
+	t.Parallel()
+
+	policy := repeater.NewPolicy(
+		repeater.WithProgression(repeater.ConstantProgression(time.Second)),
+		repeater.WithMaxRetries(math.MaxUint64),
+	)
+
+	schedule := repeater.Simulate(policy, []repeater.Result{repeater.ResultFailed, repeater.ResultAborted, repeater.ResultSucceeded})
+
+	if schedule.StopReason != repeater.StopAborted || schedule.Attempts != 2 || schedule.TotalSleep != time.Second {
+		t.Fatalf("unexpected schedule %+v", schedule)
+	}
+
+	schedule = repeater.Simulate(policy, make([]repeater.Result, 3))
+
+	if schedule.StopReason != repeater.StopNoResults || schedule.Attempts != 3 || len(schedule.Delays) != 2 {
+		t.Fatalf("unexpected schedule %+v", schedule)
+	}
+
+	schedule = repeater.Simulate(policy, nil)
+
+	if schedule.StopReason != repeater.StopNoResults || schedule.Attempts != 0 || len(schedule.Delays) != 0 {
+		t.Fatalf("unexpected schedule %+v", schedule)
+	}
+}
+
+func Test_Preview(t *testing.T) {
+	t.Parallel()
+
+	progression := repeater.NewCappedProgression(repeater.NewExponentialProgression(time.Second, 2), time.Second*5)
+
+	delays := repeater.Preview(progression, 5)
+
+	if !slices.Equal([]time.Duration{time.Second, time.Second * 2, time.Second * 4, time.Second * 5, time.Second * 5}, delays) {
+		t.Fatalf("wrong delays %v", delays)
+	}
+
+	totals := repeater.PreviewTotal(progression, 5)
+
+	if !slices.Equal([]time.Duration{time.Second, time.Second * 3, time.Second * 7, time.Second * 12, time.Second * 17}, totals) {
+		t.Fatalf("wrong totals %v", totals)
+	}
+
+	if delays := repeater.Preview(progression, -1); len(delays) != 0 {
+		t.Fatalf("unexpected delays %v", delays)
+	}
+
+	totals = repeater.PreviewTotal(repeater.NewExponentialProgression(time.Hour, 1000), 10)
+
+	if !slices.IsSorted(totals) || totals[len(totals)-1] != math.MaxInt64 {
+		t.Fatalf("totals must saturate at the max duration, actual %v", totals)
+	}
+}
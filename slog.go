@@ -0,0 +1,85 @@
+package repeater
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type slogRecorder struct {
+	logger            *slog.Logger
+	loggerFromContext func(ctx context.Context) *slog.Logger
+}
+
+type SlogRecorderOption func(s *slogRecorder)
+
+// WithLoggerFromContext makes the recorder use a request-scoped logger extracted from the repeat context,
+// the logger passed to NewSlogRecorder is used when extract returns nil
+func WithLoggerFromContext(extract func(ctx context.Context) *slog.Logger) SlogRecorderOption {
+	return func(s *slogRecorder) {
+		s.loggerFromContext = extract
+	}
+}
+
+// NewSlogRecorder logs attempts with debug level, scheduled retries with info level
+// and given up repeats with warn level
+func NewSlogRecorder(logger *slog.Logger, opts ...SlogRecorderOption) Recorder {
+	s := slogRecorder{logger: logger}
+
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	return s
+}
+
+func (s slogRecorder) log(ctx context.Context) *slog.Logger {
+	if s.loggerFromContext == nil {
+		return s.logger
+	}
+
+	logger := s.loggerFromContext(ctx)
+	if logger == nil {
+		return s.logger
+	}
+
+	return logger
+}
+
+func (s slogRecorder) AttemptStarted(ctx context.Context, attempt uint64, elapsed time.Duration) {
+	s.log(ctx).DebugContext(ctx, "repeat attempt started",
+		slog.Uint64("attempt", attempt),
+		slog.Duration("elapsed", elapsed),
+	)
+}
+
+func (s slogRecorder) AttemptFinished(ctx context.Context, attempt uint64, elapsed time.Duration, finished bool) {
+	s.log(ctx).DebugContext(ctx, "repeat attempt finished",
+		slog.Uint64("attempt", attempt),
+		slog.Duration("elapsed", elapsed),
+		slog.Bool("finished", finished),
+	)
+}
+
+func (s slogRecorder) RetryScheduled(ctx context.Context, attempt uint64, elapsed, delay time.Duration) {
+	s.log(ctx).InfoContext(ctx, "repeat retry scheduled",
+		slog.Uint64("attempt", attempt),
+		slog.Duration("elapsed", elapsed),
+		slog.Duration("delay", delay),
+	)
+}
+
+func (s slogRecorder) GaveUp(ctx context.Context, attempt uint64, elapsed time.Duration, exhausted bool) {
+	s.log(ctx).WarnContext(ctx, "repeat gave up",
+		slog.Uint64("attempt", attempt),
+		slog.Duration("elapsed", elapsed),
+		slog.Bool("exhausted", exhausted),
+	)
+}
+
+func (s slogRecorder) Stopped(ctx context.Context, attempt uint64, elapsed time.Duration) {
+	s.log(ctx).InfoContext(ctx, "repeat stopped",
+		slog.Uint64("attempt", attempt),
+		slog.Duration("elapsed", elapsed),
+	)
+}
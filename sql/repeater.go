@@ -0,0 +1,90 @@
+package sqlrepeater
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"github.com/amidgo/repeater"
+)
+
+var (
+	// A regular expression to match the error returned by Postgres when a cached
+	// prepared statement plan became stale after a schema migration.
+	cachedPlanErrorRe = regexp.MustCompile(`cached plan must not change result type`)
+
+	// A regular expression to match the error returned by Postgres when a prepared
+	// statement was dropped, e.g. after pgbouncer reassigned the server connection.
+	preparedStatementErrorRe = regexp.MustCompile(`prepared statement .* does not exist`)
+)
+
+// invalidSQLStatementName is SQLSTATE of "prepared statement does not exist" errors
+const invalidSQLStatementName = "26000"
+
+// IsStalePlan reports whether err is caused by an invalidated cached plan or a missing prepared statement,
+// driver errors exposing SQLState() like *pgconn.PgError are checked by code as well
+func IsStalePlan(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var sqlStateErr interface{ SQLState() string }
+	if errors.As(err, &sqlStateErr) && sqlStateErr.SQLState() == invalidSQLStatementName {
+		return true
+	}
+
+	msg := err.Error()
+
+	return cachedPlanErrorRe.MatchString(msg) || preparedStatementErrorRe.MatchString(msg)
+}
+
+// ResetFunc resets the driver statement cache, e.g. pgx.Conn.DeallocateAll
+type ResetFunc func(ctx context.Context) error
+
+type options struct {
+	reset ResetFunc
+}
+
+type Option func(o *options)
+
+// WithReset calls reset before retrying a stale plan error,
+// a reset error aborts the repeat
+func WithReset(reset ResetFunc) Option {
+	return func(o *options) {
+		o.reset = reset
+	}
+}
+
+// Do repeats f with policy while it fails with stale plan errors, other errors are returned without retries.
+// Errors of a repeat which gave up are wrapped in *repeater.Error
+func Do(ctx context.Context, policy repeater.Policy, f repeater.RepeatErrFunc, opts ...Option) error {
+	var o options
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	resetNeeded := false
+
+	return policy.RepeatErr(ctx, func(ctx context.Context) error {
+		if resetNeeded && o.reset != nil {
+			err := o.reset(ctx)
+			if err != nil {
+				return repeater.Abort(err)
+			}
+		}
+
+		err := f(ctx)
+
+		switch {
+		case err == nil:
+			return nil
+		case IsStalePlan(err):
+			resetNeeded = true
+
+			return err
+		default:
+			return repeater.Abort(err)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package sqlrepeater_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/amidgo/repeater"
+	sqlrepeater "github.com/amidgo/repeater/sql"
+)
+
+type sqlStateError struct {
+	code string
+}
+
+func (s *sqlStateError) Error() string {
+	return "sql state " + s.code
+}
+
+func (s *sqlStateError) SQLState() string {
+	return s.code
+}
+
+func Test_IsStalePlan(t *testing.T) {
+	t.Parallel()
+
+	for err, expected := range map[error]bool{
+		nil: false,
+		errors.New("ERROR: cached plan must not change result type (SQLSTATE 0A000)"): true,
+		errors.New(`ERROR: prepared statement "stmtcache_1" does not exist`):          true,
+		&sqlStateError{code: "26000"}: true,
+		&sqlStateError{code: "23505"}: false,
+		io.ErrUnexpectedEOF:           false,
+	} {
+		if sqlrepeater.IsStalePlan(err) != expected {
+			t.Fatalf("wrong IsStalePlan(%v), expected %t", err, expected)
+		}
+	}
+}
+
+func Test_Do(t *testing.T) {
+	t.Parallel()
+
+	policy := repeater.NewPolicy(repeater.WithMaxRetries(3))
+
+	t.Run("stale plan retried with reset", func(t *testing.T) {
+		t.Parallel()
+
+		calls, resets := 0, 0
+
+		err := sqlrepeater.Do(context.Background(), policy,
+			func(context.Context) error {
+				calls++
+				if calls < 3 {
+					return &sqlStateError{code: "26000"}
+				}
+
+				return nil
+			},
+			sqlrepeater.WithReset(func(context.Context) error {
+				resets++
+
+				return nil
+			}),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if calls != 3 || resets != 2 {
+			t.Fatalf("expected 3 calls and 2 resets, actual %d calls and %d resets", calls, resets)
+		}
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+
+		err := sqlrepeater.Do(context.Background(), policy, func(context.Context) error {
+			calls++
+
+			return io.ErrUnexpectedEOF
+		})
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("expected io.ErrUnexpectedEOF, actual %v", err)
+		}
+
+		if calls != 1 {
+			t.Fatalf("expected 1 call, actual %d", calls)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package repeater
+
+import (
+	"context"
+	"errors"
+)
+
+// DeadLetter is an item which Stage failed to process, Err is *Error of its repeat
+type DeadLetter[T any] struct {
+	Item T
+	Err  error
+}
+
+// Stage is a pipeline stage processing every item received from in with policy and sending
+// the results to out, items which gave up go to deadLetter. Items are processed one by one
+// and sends block, so a slow consumer slows the stage down and the stage slows the producer.
+// If deadLetter is nil, Stage returns the error of the first item which gave up.
+// Items stopped by a Stop error are neither sent to out nor to deadLetter.
+// Stage returns nil when in is closed or ctx.Err() when ctx is done, out and deadLetter are not closed
+func Stage[In, Out any](
+	ctx context.Context,
+	policy Policy,
+	in <-chan In,
+	out chan<- Out,
+	deadLetter chan<- DeadLetter[In],
+	process func(ctx context.Context, item In) (Out, error),
+) error {
+	for {
+		var (
+			item In
+			ok   bool
+		)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case item, ok = <-in:
+			if !ok {
+				return nil
+			}
+		}
+
+		var result Out
+
+		err := policy.RepeatErr(ctx, func(ctx context.Context) (err error) {
+			result, err = process(ctx, item)
+
+			return err
+		})
+
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.Is(err, ErrStopped):
+			continue
+		case err == nil:
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case out <- result:
+			}
+		case deadLetter == nil:
+			return err
+		default:
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case deadLetter <- DeadLetter[In]{Item: item, Err: err}:
+			}
+		}
+	}
+}
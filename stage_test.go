@@ -0,0 +1,131 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Stage(t *testing.T) {
+	t.Parallel()
+
+	errTemporary := errors.New("temporary")
+
+	in := make(chan int)
+	out := make(chan string)
+	deadLetter := make(chan repeater.DeadLetter[int])
+
+	go func() {
+		defer close(in)
+
+		for i := range 4 {
+			in <- i
+		}
+	}()
+
+	calls := map[int]int{}
+
+	process := func(_ context.Context, item int) (string, error) {
+		calls[item]++
+
+		switch {
+		case item == 1 && calls[item] == 1:
+			return "", errTemporary
+		case item == 2:
+			return "", errTemporary
+		}
+
+		return strconv.Itoa(item), nil
+	}
+
+	done := make(chan error)
+
+	go func() {
+		done <- repeater.Stage(context.Background(), repeater.NewPolicy(repeater.WithMaxRetries(1)), in, out, deadLetter, process)
+	}()
+
+	var (
+		results []string
+		dead    []repeater.DeadLetter[int]
+	)
+
+	for len(results)+len(dead) < 4 {
+		select {
+		case result := <-out:
+			results = append(results, result)
+		case letter := <-deadLetter:
+			dead = append(dead, letter)
+		}
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if !slices.Equal([]string{"0", "1", "3"}, results) {
+		t.Fatalf("wrong results %q", results)
+	}
+
+	if len(dead) != 1 || dead[0].Item != 2 || !errors.Is(dead[0].Err, errTemporary) {
+		t.Fatalf("wrong dead letters %+v", dead)
+	}
+}
+
+func Test_Stage_WithoutDeadLetter(t *testing.T) {
+	t.Parallel()
+
+	errPermanent := errors.New("permanent")
+
+	in := make(chan int, 1)
+	in <- 1
+
+	err := repeater.Stage(context.Background(), repeater.NewPolicy(), in, make(chan int), nil, func(context.Context, int) (int, error) {
+		return 0, errPermanent
+	})
+	if !errors.Is(err, errPermanent) {
+		t.Fatalf("expected permanent error, actual %v", err)
+	}
+}
+
+func Test_Stage_Stopped(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int, 2)
+	in <- 1
+	in <- 2
+	close(in)
+
+	out := make(chan int, 2)
+	deadLetter := make(chan repeater.DeadLetter[int], 2)
+
+	err := repeater.Stage(context.Background(), repeater.NewPolicy(), in, out, deadLetter, func(_ context.Context, item int) (int, error) {
+		if item == 1 {
+			return 0, repeater.Stop()
+		}
+
+		return item, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	close(out)
+	close(deadLetter)
+
+	var results []int
+	for result := range out {
+		results = append(results, result)
+	}
+
+	if !slices.Equal([]int{2}, results) {
+		t.Fatalf("wrong results, expected %v, actual %v", []int{2}, results)
+	}
+
+	if letter, ok := <-deadLetter; ok {
+		t.Fatalf("unexpected dead letter %+v", letter)
+	}
+}
@@ -0,0 +1,76 @@
+package repeater
+
+import "context"
+
+// AttemptResult is sent by Run for every finished attempt
+type AttemptResult struct {
+	// zero is the initial attempt, retries start from 1
+	Attempt  uint64
+	Finished bool
+}
+
+// Run is a repeat running in its own goroutine, started by Start
+type Run struct {
+	results  chan AttemptResult
+	done     chan struct{}
+	cancel   context.CancelFunc
+	finished bool
+}
+
+// Start runs policy.RepeatContext in a new goroutine, so the repeat can be awaited in a select loop.
+// Canceling ctx or calling Run.Cancel stops the repeat
+func Start(ctx context.Context, policy Policy, rfctx RepeatFuncContext) *Run {
+	ctx, cancel := context.WithCancel(ctx)
+
+	run := &Run{
+		results: make(chan AttemptResult, 1),
+		done:    make(chan struct{}),
+		cancel:  cancel,
+	}
+
+	go run.repeat(ctx, policy, rfctx)
+
+	return run
+}
+
+func (r *Run) repeat(ctx context.Context, policy Policy, rfctx RepeatFuncContext) {
+	defer close(r.done)
+	defer close(r.results)
+	defer r.cancel()
+
+	var attempt uint64
+
+	r.finished = policy.RepeatContext(ctx, func(ctx context.Context) bool {
+		finished := rfctx(ctx)
+
+		select {
+		case r.results <- AttemptResult{Attempt: attempt, Finished: finished}:
+		case <-ctx.Done():
+		}
+
+		attempt++
+
+		return finished
+	})
+}
+
+// Results returns the channel of attempt results, it is closed when the repeat is over.
+// The repeat waits for a result to be received before the next attempt until the run is canceled
+func (r *Run) Results() <-chan AttemptResult {
+	return r.results
+}
+
+// Done is closed when the repeat is over
+func (r *Run) Done() <-chan struct{} {
+	return r.done
+}
+
+// Finished reports whether the repeat finished successfully, it must be called after Done is closed
+func (r *Run) Finished() bool {
+	return r.finished
+}
+
+// Cancel stops the repeat, it doesn't wait for the running attempt
+func (r *Run) Cancel() {
+	r.cancel()
+}
@@ -0,0 +1,83 @@
+package repeater_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Start(t *testing.T) {
+	t.Parallel()
+
+	t.Run("finished", func(t *testing.T) {
+		t.Parallel()
+
+		policy := repeater.NewPolicy(
+			repeater.WithProgression(repeater.ConstantProgression(time.Millisecond)),
+			repeater.WithMaxRetries(3),
+		)
+
+		calls := 0
+
+		run := repeater.Start(context.Background(), policy, func(context.Context) bool {
+			calls++
+
+			return calls == 2
+		})
+
+		var results []repeater.AttemptResult
+
+		for result := range run.Results() {
+			results = append(results, result)
+		}
+
+		<-run.Done()
+
+		expected := []repeater.AttemptResult{
+			{Attempt: 0, Finished: false},
+			{Attempt: 1, Finished: true},
+		}
+
+		if !slices.Equal(expected, results) {
+			t.Fatalf("wrong results, expected %v, actual %v", expected, results)
+		}
+
+		if !run.Finished() {
+			t.Fatal("expected finished run")
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		t.Parallel()
+
+		policy := repeater.NewPolicy(
+			repeater.WithProgression(repeater.ConstantProgression(time.Hour)),
+			repeater.WithMaxRetries(3),
+		)
+
+		run := repeater.Start(context.Background(), policy, func(context.Context) bool { return false })
+
+		result := <-run.Results()
+		if result.Attempt != 0 || result.Finished {
+			t.Fatalf("unexpected result %+v", result)
+		}
+
+		run.Cancel()
+
+		timer := time.NewTimer(time.Second)
+		defer timer.Stop()
+
+		select {
+		case <-run.Done():
+		case <-timer.C:
+			t.Fatal("run is not stopped by Cancel")
+		}
+
+		if run.Finished() {
+			t.Fatal("expected not finished run")
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package repeater
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of repeater counters since construction
+type Stats struct {
+	// number of Repeat and RepeatContext calls
+	Calls uint64
+	// number of repeat func calls, including initial ones
+	Attempts uint64
+	// number of repeats finished successfully
+	Successes uint64
+	// number of repeats aborted by the repeat func, see Abort
+	Aborts uint64
+	// number of repeats stopped by context, canceled or because of its deadline
+	Canceled uint64
+	// number of repeats stopped by a Stop error, they neither succeeded nor failed
+	Stops uint64
+	// number of repeats which exhausted retry count
+	Exceeded uint64
+	// sum of scheduled positive delays
+	TotalSleep time.Duration
+}
+
+// Stats returns counters collected since New, it is safe to call concurrently with repeats
+func (r *Repeater) Stats() Stats {
+	return r.stats.snapshot()
+}
+
+type statsRecorder struct {
+	calls      atomic.Uint64
+	attempts   atomic.Uint64
+	successes  atomic.Uint64
+	aborts     atomic.Uint64
+	cancels    atomic.Uint64
+	stops      atomic.Uint64
+	exceeded   atomic.Uint64
+	totalSleep atomic.Int64
+}
+
+func (s *statsRecorder) snapshot() Stats {
+	return Stats{
+		Calls:      s.calls.Load(),
+		Attempts:   s.attempts.Load(),
+		Successes:  s.successes.Load(),
+		Aborts:     s.aborts.Load(),
+		Canceled:   s.cancels.Load(),
+		Stops:      s.stops.Load(),
+		Exceeded:   s.exceeded.Load(),
+		TotalSleep: time.Duration(s.totalSleep.Load()),
+	}
+}
+
+func (s *statsRecorder) AttemptStarted(context.Context, uint64, time.Duration) {
+	s.attempts.Add(1)
+}
+
+// calls are counted by the last event of a repeat,
+// because a repeat stopped by context may finish without attempts
+func (s *statsRecorder) AttemptFinished(_ context.Context, _ uint64, _ time.Duration, finished bool) {
+	if finished {
+		s.calls.Add(1)
+		s.successes.Add(1)
+	}
+}
+
+func (s *statsRecorder) RetryScheduled(_ context.Context, _ uint64, _, delay time.Duration) {
+	if delay > 0 {
+		s.totalSleep.Add(int64(delay))
+	}
+}
+
+func (s *statsRecorder) GaveUp(_ context.Context, _ uint64, _ time.Duration, exhausted bool) {
+	s.calls.Add(1)
+
+	if exhausted {
+		s.exceeded.Add(1)
+	} else {
+		s.aborts.Add(1)
+	}
+}
+
+func (s *statsRecorder) canceled(context.Context, uint64, time.Duration) {
+	s.calls.Add(1)
+	s.cancels.Add(1)
+}
+
+func (s *statsRecorder) Stopped(context.Context, uint64, time.Duration) {
+	s.calls.Add(1)
+	s.stops.Add(1)
+}
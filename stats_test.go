@@ -0,0 +1,103 @@
+package repeater_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Repeater_Stats(t *testing.T) {
+	t.Parallel()
+
+	rp := repeater.New(repeater.ConstantProgression(time.Millisecond))
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			calls := 0
+
+			rp.Repeat(func() bool {
+				calls++
+
+				return calls == 2
+			}, 2)
+		}()
+	}
+
+	wg.Wait()
+
+	rp.Repeat(func() bool { return false }, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	rp.RepeatContext(ctx, func(context.Context) bool { return false }, 2)
+
+	expected := repeater.Stats{
+		Calls:      12,
+		Attempts:   23,
+		Successes:  10,
+		Canceled:   1,
+		Exceeded:   1,
+		TotalSleep: time.Millisecond * 12,
+	}
+
+	actual := rp.Stats()
+	if expected != actual {
+		t.Fatalf("wrong stats, expected %+v, actual %+v", expected, actual)
+	}
+}
+
+func Test_Repeater_Stats_Stopped(t *testing.T) {
+	t.Parallel()
+
+	rp := repeater.New(repeater.ConstantProgression(0))
+
+	rp.RepeatErr(context.Background(), func(context.Context) error { return repeater.Stop() }, 2)
+
+	expected := repeater.Stats{
+		Calls:    1,
+		Attempts: 1,
+		Stops:    1,
+	}
+
+	actual := rp.Stats()
+	if expected != actual {
+		t.Fatalf("wrong stats, expected %+v, actual %+v", expected, actual)
+	}
+}
+
+func Test_Repeater_Stats_Canceled(t *testing.T) {
+	t.Parallel()
+
+	rp := repeater.New(repeater.ConstantProgression(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	rp.RepeatErr(ctx, func(context.Context) error {
+		cancel()
+
+		return context.Canceled
+	}, 2)
+	rp.RepeatErr(context.Background(), func(context.Context) error { return repeater.Abortf("bad request") }, 2)
+
+	expected := repeater.Stats{
+		Calls:    2,
+		Attempts: 2,
+		Aborts:   1,
+		Canceled: 1,
+	}
+
+	actual := rp.Stats()
+	if expected != actual {
+		t.Fatalf("wrong stats, expected %+v, actual %+v", expected, actual)
+	}
+}
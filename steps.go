@@ -0,0 +1,84 @@
+package repeater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Step is a named part of a multi-step operation run by Steps
+type Step struct {
+	Name string
+	Func RepeatErrFunc
+}
+
+// CheckpointStore persists names of completed steps, so Steps skips them when it is run again,
+// e.g. a database table keyed by the operation id
+type CheckpointStore interface {
+	Completed(ctx context.Context, name string) (bool, error)
+	Complete(ctx context.Context, name string) error
+}
+
+// Steps runs steps in order, every step is repeated with policy independently and
+// is checkpointed in store after it succeeded. Steps completed by previous runs are skipped,
+// so a failed operation resumes from the failed step instead of replaying side effects of the earlier ones.
+// The returned error names the failed step and wraps its *Error or the store error.
+// A step returning a Stop error ends the operation without checkpointing the step,
+// its stop error is returned as is, so the operation neither succeeded nor failed
+func Steps(ctx context.Context, policy Policy, store CheckpointStore, steps ...Step) error {
+	for _, step := range steps {
+		completed, err := store.Completed(ctx, step.Name)
+		if err != nil {
+			return fmt.Errorf("step %q: load checkpoint: %w", step.Name, err)
+		}
+
+		if completed {
+			continue
+		}
+
+		err = policy.RepeatErr(ctx, step.Func)
+		if errors.Is(err, ErrStopped) {
+			return err
+		}
+
+		if err != nil {
+			return fmt.Errorf("step %q: %w", step.Name, err)
+		}
+
+		err = store.Complete(ctx, step.Name)
+		if err != nil {
+			return fmt.Errorf("step %q: save checkpoint: %w", step.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// MemoryCheckpoints is CheckpointStore keeping checkpoints in memory, the zero value is ready to use
+type MemoryCheckpoints struct {
+	mu        sync.Mutex
+	completed map[string]struct{}
+}
+
+func (m *MemoryCheckpoints) Completed(_ context.Context, name string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.completed[name]
+
+	return ok, nil
+}
+
+func (m *MemoryCheckpoints) Complete(_ context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.completed == nil {
+		m.completed = make(map[string]struct{})
+	}
+
+	m.completed[name] = struct{}{}
+
+	return nil
+}
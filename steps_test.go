@@ -0,0 +1,101 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Steps(t *testing.T) {
+	t.Parallel()
+
+	errTemporary := errors.New("temporary")
+
+	var (
+		calls []string
+		fail  = true
+	)
+
+	step := func(name string) repeater.Step {
+		return repeater.Step{
+			Name: name,
+			Func: func(context.Context) error {
+				calls = append(calls, name)
+
+				if name == "charge" && fail {
+					return errTemporary
+				}
+
+				return nil
+			},
+		}
+	}
+
+	steps := []repeater.Step{step("reserve"), step("charge"), step("ship")}
+
+	store := &repeater.MemoryCheckpoints{}
+	policy := repeater.NewPolicy(repeater.WithMaxRetries(1))
+
+	err := repeater.Steps(context.Background(), policy, store, steps...)
+	if !errors.Is(err, errTemporary) || !strings.HasPrefix(err.Error(), `step "charge": `) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	fail = false
+
+	err = repeater.Steps(context.Background(), policy, store, steps...)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := []string{"reserve", "charge", "charge", "charge", "ship"}
+
+	if !slices.Equal(expected, calls) {
+		t.Fatalf("wrong calls, expected %q, actual %q", expected, calls)
+	}
+}
+
+func Test_Steps_Stopped(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	steps := []repeater.Step{
+		{
+			Name: "reserve",
+			Func: func(context.Context) error {
+				calls = append(calls, "reserve")
+
+				return repeater.Stop()
+			},
+		},
+		{
+			Name: "ship",
+			Func: func(context.Context) error {
+				calls = append(calls, "ship")
+
+				return nil
+			},
+		},
+	}
+
+	store := &repeater.MemoryCheckpoints{}
+
+	err := repeater.Steps(context.Background(), repeater.NewPolicy(repeater.WithMaxRetries(1)), store, steps...)
+	if err != repeater.ErrStopped {
+		t.Fatalf("wrong error, expected %v, actual %v", repeater.ErrStopped, err)
+	}
+
+	if !slices.Equal([]string{"reserve"}, calls) {
+		t.Fatalf("wrong calls, expected %q, actual %q", []string{"reserve"}, calls)
+	}
+
+	completed, _ := store.Completed(context.Background(), "reserve")
+	if completed {
+		t.Fatal("stopped step is checkpointed")
+	}
+}
@@ -0,0 +1,113 @@
+package repeater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// PanicError is the error of a supervised func which panicked
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", p.Value)
+}
+
+// Supervisor runs a func in a goroutine and restarts it when it fails, see Supervise
+type Supervisor struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	health *HealthMonitor
+
+	stopOnce sync.Once
+	stopped  atomic.Bool
+	err      error
+}
+
+// Supervise runs f in a new goroutine and restarts it after errors and panics with delays of progression,
+// panics are recovered into *PanicError. The progression is reset after f runs at least WithStableAfter
+// before failing. Supervision ends when f returns nil or an Abort error, ctx is done or Stop is called
+func Supervise(ctx context.Context, progression DurationProgression, f RepeatErrFunc, opts ...LoopOption) *Supervisor {
+	ctx, cancel := context.WithCancel(ctx)
+
+	s := &Supervisor{
+		cancel: cancel,
+		done:   make(chan struct{}),
+		health: NewHealthMonitor(),
+	}
+
+	go s.run(ctx, progression, f, opts)
+
+	return s
+}
+
+func (s *Supervisor) run(ctx context.Context, progression DurationProgression, f RepeatErrFunc, opts []LoopOption) {
+	defer close(s.done)
+	defer s.cancel()
+
+	var o loopOptions
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	o.health, o.stopOnSuccess = s.health, true
+
+	s.err = loop(ctx, progression, func(ctx context.Context) error {
+		return callRecovered(ctx, f)
+	}, o)
+}
+
+func callRecovered(ctx context.Context, f RepeatErrFunc) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+
+	return f(ctx)
+}
+
+// Stop cancels the supervised func context and waits until it returns
+func (s *Supervisor) Stop() {
+	s.signalStop()
+
+	<-s.done
+}
+
+func (s *Supervisor) signalStop() {
+	s.stopOnce.Do(func() {
+		s.stopped.Store(true)
+		s.cancel()
+	})
+}
+
+// Health returns the state of the supervised func, the Supervisor keeps its own HealthMonitor,
+// so WithHealthMonitor passed to Supervise is ignored
+func (s *Supervisor) Health() Health {
+	return s.health.Health()
+}
+
+// Done is closed when the supervision ends
+func (s *Supervisor) Done() <-chan struct{} {
+	return s.done
+}
+
+// Wait waits for the supervision end and returns the Abort error of f or the ctx error,
+// nil means f returned nil or Stop was called
+func (s *Supervisor) Wait() error {
+	<-s.done
+
+	if s.stopped.Load() && errors.Is(s.err, context.Canceled) {
+		return nil
+	}
+
+	return s.err
+}
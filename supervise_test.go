@@ -0,0 +1,77 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Supervise(t *testing.T) {
+	t.Parallel()
+
+	t.Run("restarts after errors and panics", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int64
+
+		s := repeater.Supervise(context.Background(), repeater.ConstantProgression(time.Millisecond), func(context.Context) error {
+			switch calls.Add(1) {
+			case 1:
+				return io.ErrUnexpectedEOF
+			case 2:
+				panic("boom")
+			default:
+				return nil
+			}
+		})
+
+		err := s.Wait()
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if calls.Load() != 3 {
+			t.Fatalf("wrong calls, expected 3, actual %d", calls.Load())
+		}
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		t.Parallel()
+
+		s := repeater.Supervise(context.Background(), repeater.ConstantProgression(0), func(context.Context) error {
+			return repeater.Abort(io.EOF)
+		})
+
+		err := s.Wait()
+		if !errors.Is(err, io.EOF) {
+			t.Fatalf("expected abort error, actual %v", err)
+		}
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		t.Parallel()
+
+		started := make(chan struct{})
+
+		s := repeater.Supervise(context.Background(), repeater.ConstantProgression(0), func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+
+			return ctx.Err()
+		})
+
+		<-started
+
+		s.Stop()
+
+		err := s.Wait()
+		if err != nil {
+			t.Fatalf("unexpected error after stop %v", err)
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package repeater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+type supervisedLoop struct {
+	name        string
+	progression DurationProgression
+	f           RepeatErrFunc
+	opts        []LoopOption
+}
+
+// SupervisorGroup supervises many named funcs with their own progressions and starts and stops them together,
+// it is safe for concurrent use
+type SupervisorGroup struct {
+	mu          sync.Mutex
+	loops       []supervisedLoop
+	supervisors map[string]*Supervisor
+}
+
+func NewSupervisorGroup() *SupervisorGroup {
+	return &SupervisorGroup{
+		supervisors: make(map[string]*Supervisor),
+	}
+}
+
+// Add registers f supervised with progression and opts under the unique name, see Supervise,
+// funcs added after Start are not started until the next Start
+func (g *SupervisorGroup) Add(name string, progression DurationProgression, f RepeatErrFunc, opts ...LoopOption) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if slices.ContainsFunc(g.loops, func(l supervisedLoop) bool { return l.name == name }) {
+		panic(fmt.Sprintf("repeater: supervised func %q is already added", name))
+	}
+
+	g.loops = append(g.loops, supervisedLoop{name: name, progression: progression, f: f, opts: opts})
+}
+
+// Start supervises every added func which is not running with ctx
+func (g *SupervisorGroup) Start(ctx context.Context) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, l := range g.loops {
+		s, ok := g.supervisors[l.name]
+		if ok && !isDone(s.Done()) {
+			continue
+		}
+
+		g.supervisors[l.name] = Supervise(ctx, l.progression, l.f, l.opts...)
+	}
+}
+
+// Stop stops every supervised func and waits until they return
+func (g *SupervisorGroup) Stop() {
+	supervisors := g.snapshot()
+
+	for _, s := range supervisors {
+		s.signalStop()
+	}
+
+	for _, s := range supervisors {
+		<-s.Done()
+	}
+}
+
+// Wait waits until every supervised func ends and returns their errors joined, see Supervisor.Wait
+func (g *SupervisorGroup) Wait() error {
+	var errs []error
+
+	for name, s := range g.snapshot() {
+		err := s.Wait()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Healths returns the health of every started func by name
+func (g *SupervisorGroup) Healths() map[string]Health {
+	healths := make(map[string]Health)
+
+	for name, s := range g.snapshot() {
+		healths[name] = s.Health()
+	}
+
+	return healths
+}
+
+// Health returns the health of the worst started func, failing funcs are the worst,
+// stopped and starting ones follow, so it is healthy only when every func is running,
+// an empty group is starting
+func (g *SupervisorGroup) Health() Health {
+	worst := Health{State: HealthStarting}
+	worstRank := -1
+
+	healths := g.Healths()
+
+	for _, name := range slices.Sorted(maps.Keys(healths)) {
+		h := healths[name]
+		if rank := healthRank(h.State); rank > worstRank {
+			worst, worstRank = h, rank
+		}
+	}
+
+	return worst
+}
+
+func healthRank(state HealthState) int {
+	switch state {
+	case HealthRunning:
+		return 0
+	case HealthStarting:
+		return 1
+	case HealthStopped:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func (g *SupervisorGroup) snapshot() map[string]*Supervisor {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return maps.Clone(g.supervisors)
+}
+
+func isDone(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
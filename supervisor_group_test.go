@@ -0,0 +1,79 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_SupervisorGroup(t *testing.T) {
+	t.Parallel()
+
+	g := repeater.NewSupervisorGroup()
+
+	if g.Health().State != repeater.HealthStarting {
+		t.Fatalf("wrong empty group state %s", g.Health().State)
+	}
+
+	g.Add("blocking", repeater.ConstantProgression(0), func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+
+	g.Add("failing", repeater.ConstantProgression(time.Hour), func(context.Context) error {
+		return io.ErrUnexpectedEOF
+	})
+
+	g.Start(context.Background())
+
+	for g.Healths()["failing"].State != repeater.HealthFailing {
+		time.Sleep(time.Millisecond)
+	}
+
+	health := g.Health()
+	if health.State != repeater.HealthFailing || !errors.Is(health.LastError, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected group health of the failing func, actual %+v", health)
+	}
+
+	healths := g.Healths()
+	if len(healths) != 2 || healths["blocking"].State != repeater.HealthRunning {
+		t.Fatalf("unexpected healths %+v", healths)
+	}
+
+	g.Stop()
+
+	err := g.Wait()
+	if err != nil {
+		t.Fatalf("unexpected error after stop %v", err)
+	}
+
+	if g.Health().State != repeater.HealthStopped {
+		t.Fatalf("wrong state after stop %s", g.Health().State)
+	}
+}
+
+func Test_SupervisorGroup_Wait(t *testing.T) {
+	t.Parallel()
+
+	g := repeater.NewSupervisorGroup()
+
+	g.Add("aborted", repeater.ConstantProgression(0), func(context.Context) error {
+		return repeater.Abort(io.EOF)
+	})
+
+	g.Add("finished", repeater.ConstantProgression(0), func(context.Context) error {
+		return nil
+	})
+
+	g.Start(context.Background())
+
+	err := g.Wait()
+	if !errors.Is(err, io.EOF) || err.Error() != "aborted: EOF" {
+		t.Fatalf("expected named abort error, actual %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package temporalrepeater
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+// RetryPolicy is a copy of go.temporal.io/sdk/temporal.RetryPolicy with the same fields in the same order,
+// so the types convert to each other without the module depending on the Temporal SDK
+type RetryPolicy struct {
+	// zero means 1s
+	InitialInterval time.Duration
+	// zero means 2
+	BackoffCoefficient float64
+	// zero means 100 initial intervals
+	MaximumInterval time.Duration
+	// attempts including the initial one, zero means no limit
+	MaximumAttempts int32
+	// type names of errors which are not retried
+	NonRetryableErrorTypes []string
+}
+
+const (
+	defaultInitialInterval    = time.Second
+	defaultBackoffCoefficient = 2
+	defaultMaximumIntervals   = 100
+)
+
+// ToPolicy converts p to an exponential capped policy applying Temporal defaults to zero fields,
+// opts are passed to repeater.New. Use AbortNonRetryable to honor NonRetryableErrorTypes
+func ToPolicy(p RetryPolicy, opts ...repeater.Option) (repeater.Policy, error) {
+	initial := p.InitialInterval
+	if initial <= 0 {
+		initial = defaultInitialInterval
+	}
+
+	coefficient := p.BackoffCoefficient
+	if coefficient <= 0 {
+		coefficient = defaultBackoffCoefficient
+	}
+
+	maximum := p.MaximumInterval
+	if maximum <= 0 {
+		maximum = initial * defaultMaximumIntervals
+	}
+
+	retryCount := uint64(math.MaxUint64)
+	if p.MaximumAttempts > 0 {
+		retryCount = uint64(p.MaximumAttempts) - 1
+	}
+
+	cfg := repeater.Config{
+		Backoff:    repeater.ExponentialBackoff,
+		Initial:    initial,
+		Factor:     coefficient,
+		Cap:        maximum,
+		MaxRetries: retryCount,
+	}
+
+	return cfg.Build(opts...)
+}
+
+// AbortNonRetryable wraps errors of rf with type names listed in p.NonRetryableErrorTypes in repeater.Abort
+func AbortNonRetryable(p RetryPolicy, rf repeater.RepeatErrFunc) repeater.RepeatErrFunc {
+	return func(ctx context.Context) error {
+		err := rf(ctx)
+		if err != nil && NonRetryable(p, err) {
+			return repeater.Abort(err)
+		}
+
+		return err
+	}
+}
+
+// NonRetryable reports whether err or any error it wraps has a type name listed in p.NonRetryableErrorTypes,
+// the type name is the result of the Type method like in Temporal application errors
+// or the Go type name without the package and pointer, e.g. "NotFoundError" for *store.NotFoundError
+func NonRetryable(p RetryPolicy, err error) bool {
+	if len(p.NonRetryableErrorTypes) == 0 {
+		return false
+	}
+
+	return walkErrors(err, func(err error) bool {
+		return slices.Contains(p.NonRetryableErrorTypes, errorType(err))
+	})
+}
+
+func errorType(err error) string {
+	if typed, ok := err.(interface{ Type() string }); ok {
+		return typed.Type()
+	}
+
+	t := reflect.TypeOf(err)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Name()
+}
+
+// walkErrors calls match for err and every error it wraps until match returns true
+func walkErrors(err error, match func(err error) bool) bool {
+	if err == nil {
+		return false
+	}
+
+	if match(err) {
+		return true
+	}
+
+	switch wrapper := err.(type) {
+	case interface{ Unwrap() error }:
+		return walkErrors(wrapper.Unwrap(), match)
+	case interface{ Unwrap() []error }:
+		return slices.ContainsFunc(wrapper.Unwrap(), func(err error) bool { return walkErrors(err, match) })
+	default:
+		return false
+	}
+}
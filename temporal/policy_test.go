@@ -0,0 +1,100 @@
+package temporalrepeater_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	temporalrepeater "github.com/amidgo/repeater/temporal"
+)
+
+type NotFoundError struct{}
+
+func (*NotFoundError) Error() string {
+	return "not found"
+}
+
+type applicationError struct {
+	typ string
+}
+
+func (a applicationError) Error() string {
+	return "application error"
+}
+
+func (a applicationError) Type() string {
+	return a.typ
+}
+
+func Test_ToPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy, err := temporalrepeater.ToPolicy(temporalrepeater.RetryPolicy{
+		InitialInterval:    time.Millisecond * 100,
+		BackoffCoefficient: 3,
+		MaximumInterval:    time.Second,
+		MaximumAttempts:    5,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if policy.RetryCount() != 4 {
+		t.Fatalf("wrong retry count, expected 4, actual %d", policy.RetryCount())
+	}
+
+	progression := policy.Repeater().Progression()
+
+	for attempt, expected := range []time.Duration{time.Millisecond * 100, time.Millisecond * 300, time.Millisecond * 900, time.Second} {
+		if d := progression.Duration(uint64(attempt)); d != expected {
+			t.Fatalf("wrong duration of attempt %d, expected %s, actual %s", attempt, expected, d)
+		}
+	}
+
+	policy, err = temporalrepeater.ToPolicy(temporalrepeater.RetryPolicy{})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if policy.RetryCount() != math.MaxUint64 || policy.Repeater().Progression().Duration(20) != time.Second*100 {
+		t.Fatalf("defaults are not applied, retry count %d", policy.RetryCount())
+	}
+}
+
+func Test_AbortNonRetryable(t *testing.T) {
+	t.Parallel()
+
+	p := temporalrepeater.RetryPolicy{NonRetryableErrorTypes: []string{"NotFoundError", "InvalidArgument"}}
+
+	for _, test := range []struct {
+		err      error
+		expected bool
+	}{
+		{err: &NotFoundError{}, expected: true},
+		{err: fmt.Errorf("get user: %w", &NotFoundError{}), expected: true},
+		{err: applicationError{typ: "InvalidArgument"}, expected: true},
+		{err: errors.Join(errors.New("first"), applicationError{typ: "InvalidArgument"}), expected: true},
+		{err: applicationError{typ: "Unavailable"}},
+		{err: errors.New("timeout")},
+	} {
+		if temporalrepeater.NonRetryable(p, test.err) != test.expected {
+			t.Fatalf("wrong non retryable of %v, expected %t", test.err, test.expected)
+		}
+	}
+
+	calls := 0
+
+	err := repeater.RepeatErr(context.Background(), repeater.ConstantProgression(0), temporalrepeater.AbortNonRetryable(p, func(context.Context) error {
+		calls++
+
+		return &NotFoundError{}
+	}), 3)
+
+	if calls != 1 || !repeater.IsAbort(err) {
+		t.Fatalf("expected abort after the first call, actual %v after %d calls", err, calls)
+	}
+}
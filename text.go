@@ -0,0 +1,128 @@
+package repeater
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var ErrProgressionNotMarshalable = errors.New("progression doesn't implement encoding.TextMarshaler")
+
+// built-in progressions are encoded with the ParseConfig backoff syntax:
+// const(1s), arith(1s,500ms), fib(10ms), exp(100ms,2)
+var (
+	_ encoding.TextMarshaler   = ConstantProgression(0)
+	_ encoding.TextUnmarshaler = (*ConstantProgression)(nil)
+	_ encoding.TextMarshaler   = ArifmeticProggression{}
+	_ encoding.TextUnmarshaler = (*ArifmeticProggression)(nil)
+	_ encoding.TextMarshaler   = FibonacciProgression(0)
+	_ encoding.TextUnmarshaler = (*FibonacciProgression)(nil)
+	_ encoding.TextMarshaler   = ExponentialProgression{}
+	_ encoding.TextUnmarshaler = (*ExponentialProgression)(nil)
+)
+
+func (p ConstantProgression) MarshalText() ([]byte, error) {
+	return fmt.Appendf(nil, "const(%s)", time.Duration(p)), nil
+}
+
+func (p *ConstantProgression) UnmarshalText(text []byte) error {
+	cfg, err := parseProgressionText(text, ConstantBackoff)
+	if err != nil {
+		return err
+	}
+
+	*p = ConstantProgression(cfg.Initial)
+
+	return nil
+}
+
+func (a ArifmeticProggression) MarshalText() ([]byte, error) {
+	return fmt.Appendf(nil, "arith(%s,%s)", a.initial, a.delta), nil
+}
+
+func (a *ArifmeticProggression) UnmarshalText(text []byte) error {
+	cfg, err := parseProgressionText(text, ArifmeticBackoff)
+	if err != nil {
+		return err
+	}
+
+	*a = NewArifmeticProgression(cfg.Initial, cfg.Delta)
+
+	return nil
+}
+
+func (s FibonacciProgression) MarshalText() ([]byte, error) {
+	return fmt.Appendf(nil, "fib(%s)", time.Duration(s)), nil
+}
+
+func (s *FibonacciProgression) UnmarshalText(text []byte) error {
+	cfg, err := parseProgressionText(text, FibonacciBackoff)
+	if err != nil {
+		return err
+	}
+
+	*s = FibonacciProgression(cfg.Initial)
+
+	return nil
+}
+
+func (e ExponentialProgression) MarshalText() ([]byte, error) {
+	return fmt.Appendf(nil, "exp(%s,%s)", e.initial, strconv.FormatFloat(e.factor, 'g', -1, 64)), nil
+}
+
+func (e *ExponentialProgression) UnmarshalText(text []byte) error {
+	cfg, err := parseProgressionText(text, ExponentialBackoff)
+	if err != nil {
+		return err
+	}
+
+	*e = NewExponentialProgression(cfg.Initial, cfg.Factor)
+
+	return nil
+}
+
+// ProgressionValue holds any built-in progression in config structs,
+// the progression kind is taken from the text
+type ProgressionValue struct {
+	DurationProgression
+}
+
+func (p ProgressionValue) MarshalText() ([]byte, error) {
+	m, ok := p.DurationProgression.(encoding.TextMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrProgressionNotMarshalable, p.DurationProgression)
+	}
+
+	return m.MarshalText()
+}
+
+func (p *ProgressionValue) UnmarshalText(text []byte) error {
+	cfg, err := parseProgressionText(text, "")
+	if err != nil {
+		return err
+	}
+
+	p.DurationProgression, err = cfg.progression()
+
+	return err
+}
+
+// parseProgressionText parses a plain backoff expression, if kind is empty any kind is accepted
+func parseProgressionText(text []byte, kind BackoffKind) (Config, error) {
+	cfg, err := parseBackoff(string(text))
+	if err != nil {
+		return Config{}, fmt.Errorf("%w: %q: %w", ErrInvalidConfig, text, err)
+	}
+
+	if kind != "" && cfg.Backoff != kind {
+		return Config{}, fmt.Errorf("%w: %q: expected %s backoff, got %s", ErrInvalidConfig, text, kind, cfg.Backoff)
+	}
+
+	if cfg.Cap != 0 || cfg.Jitter != 0 {
+		return Config{}, fmt.Errorf("%w: %q: cap and jitter are not supported by progression text", ErrInvalidConfig, text)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,107 @@
+package repeater_test
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+type textProgression interface {
+	repeater.DurationProgression
+	encoding.TextMarshaler
+}
+
+func Test_Progression_TextRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		progression textProgression
+		text        string
+		target      encoding.TextUnmarshaler
+	}{
+		{repeater.ConstantProgression(time.Second), "const(1s)", new(repeater.ConstantProgression)},
+		{repeater.NewArifmeticProgression(time.Second, time.Millisecond*500), "arith(1s,500ms)", new(repeater.ArifmeticProggression)},
+		{repeater.FibonacciProgression(time.Millisecond * 10), "fib(10ms)", new(repeater.FibonacciProgression)},
+		{repeater.NewExponentialProgression(time.Millisecond*100, 1.5), "exp(100ms,1.5)", new(repeater.ExponentialProgression)},
+	} {
+		text, err := tc.progression.MarshalText()
+		if err != nil {
+			t.Fatalf("marshal %T: %s", tc.progression, err)
+		}
+
+		if string(text) != tc.text {
+			t.Fatalf("wrong text, expected %s, actual %s", tc.text, text)
+		}
+
+		err = tc.target.UnmarshalText(text)
+		if err != nil {
+			t.Fatalf("unmarshal %s: %s", text, err)
+		}
+
+		for attempt := range uint64(5) {
+			expected := tc.progression.Duration(attempt)
+			actual := tc.target.(repeater.DurationProgression).Duration(attempt)
+
+			if expected != actual {
+				t.Fatalf("wrong %s duration at %d attempt, expected %s, actual %s", text, attempt, expected, actual)
+			}
+		}
+	}
+}
+
+func Test_Progression_UnmarshalText_WrongKind(t *testing.T) {
+	t.Parallel()
+
+	var progression repeater.ConstantProgression
+
+	err := progression.UnmarshalText([]byte("fib(1s)"))
+	if !errors.Is(err, repeater.ErrInvalidConfig) {
+		t.Fatalf("wrong error, expected %s, actual %v", repeater.ErrInvalidConfig, err)
+	}
+
+	err = progression.UnmarshalText([]byte("const(1s,cap=2s)"))
+	if !errors.Is(err, repeater.ErrInvalidConfig) {
+		t.Fatalf("wrong error, expected %s, actual %v", repeater.ErrInvalidConfig, err)
+	}
+}
+
+func Test_ProgressionValue(t *testing.T) {
+	t.Parallel()
+
+	type serviceConfig struct {
+		Backoff repeater.ProgressionValue `json:"backoff"`
+	}
+
+	var cfg serviceConfig
+
+	err := json.Unmarshal([]byte(`{"backoff": "exp(100ms,2)"}`), &cfg)
+	if err != nil {
+		t.Fatalf("unmarshal config: %s", err)
+	}
+
+	if d := cfg.Backoff.Duration(2); d != time.Millisecond*400 {
+		t.Fatalf("wrong duration, expected %s, actual %s", time.Millisecond*400, d)
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal config: %s", err)
+	}
+
+	if string(data) != `{"backoff":"exp(100ms,2)"}` {
+		t.Fatalf("wrong marshaled config: %s", data)
+	}
+
+	_, err = json.Marshal(serviceConfig{
+		Backoff: repeater.ProgressionValue{
+			DurationProgression: repeater.NewCappedProgression(repeater.ConstantProgression(time.Second), time.Second),
+		},
+	})
+	if !errors.Is(err, repeater.ErrProgressionNotMarshalable) {
+		t.Fatalf("wrong error, expected %s, actual %v", repeater.ErrProgressionNotMarshalable, err)
+	}
+}
@@ -0,0 +1,116 @@
+package repeater
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Ticker sends the time of the initial attempt and of every planned retry on C, see NewTicker
+type Ticker struct {
+	// C receives a tick per attempt, it is closed when the ticker is done
+	C <-chan time.Time
+
+	cancel   context.CancelFunc
+	done     chan struct{}
+	stopOnce sync.Once
+	stopped  atomic.Bool
+	err      error
+}
+
+func NewTicker(ctx context.Context, progression DurationProgression, retryCount uint64) *Ticker {
+	rp := New(progression)
+
+	return rp.Ticker(ctx, retryCount)
+}
+
+// Ticker starts a goroutine sending a tick on C immediately and after every delay of the progression,
+// the delay starts when the previous tick is received. The ticker is done after retryCount retries,
+// when ctx is done or Stop is called, so the caller finishes or aborts the repeat with Stop:
+//
+//	ticker := rp.Ticker(ctx, 3)
+//	defer ticker.Stop()
+//
+//	for range ticker.C {
+//		if err := send(ctx); err == nil {
+//			ticker.Stop()
+//		}
+//	}
+//
+//	return ticker.Err()
+func (r *Repeater) Ticker(ctx context.Context, retryCount uint64) *Ticker {
+	ctx, cancel := context.WithCancel(ctx)
+
+	c := make(chan time.Time)
+
+	t := &Ticker{
+		C:      c,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go t.run(ctx, r, c, retryCount)
+
+	return t
+}
+
+func (t *Ticker) run(ctx context.Context, r *Repeater, c chan<- time.Time, retryCount uint64) {
+	defer close(t.done)
+	defer close(c)
+	defer t.cancel()
+
+	state := r.startClocked()
+
+	var attempts uint64
+
+	r.repeatContext(ctx, &state, func(ctx context.Context) bool {
+		select {
+		case c <- time.Now():
+			attempts++
+		case <-ctx.Done():
+		}
+
+		return false
+	}, retryCount)
+
+	err := &Error{
+		Attempts: attempts,
+		Elapsed:  state.elapsed(),
+		Exceeded: state.exhausted,
+		Cause:    state.errCause(),
+		Context:  state.ctxErr,
+		InSleep:  state.inSleep,
+	}
+
+	if !state.exhausted {
+		err.Last = context.Cause(ctx)
+	}
+
+	t.err = err
+}
+
+// Stop stops the ticker and waits until it is done, C is not sent to after Stop returns
+func (t *Ticker) Stop() {
+	t.stopOnce.Do(func() {
+		t.stopped.Store(true)
+		t.cancel()
+	})
+
+	<-t.done
+}
+
+// Done is closed when the ticker is done
+func (t *Ticker) Done() <-chan struct{} {
+	return t.done
+}
+
+// Err returns nil if the ticker was stopped by Stop, otherwise *Error of the repeat which gave up,
+// Exceeded is set when the retries were exhausted. It must be called after Done is closed
+func (t *Ticker) Err() error {
+	if t.stopped.Load() {
+		return nil
+	}
+
+	return t.err
+}
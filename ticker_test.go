@@ -0,0 +1,73 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Ticker(t *testing.T) {
+	t.Parallel()
+
+	ticker := repeater.NewTicker(context.Background(), repeater.ConstantProgression(time.Millisecond), 5)
+
+	ticks := 0
+
+	for range ticker.C {
+		ticks++
+
+		if ticks == 3 {
+			ticker.Stop()
+		}
+	}
+
+	if ticks != 3 {
+		t.Fatalf("wrong ticks, expected 3, actual %d", ticks)
+	}
+
+	if err := ticker.Err(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	ticker = repeater.NewTicker(context.Background(), repeater.ConstantProgression(time.Millisecond), 2)
+
+	ticks = 0
+
+	for range ticker.C {
+		ticks++
+	}
+
+	<-ticker.Done()
+
+	var repeatErr *repeater.Error
+	if !errors.As(ticker.Err(), &repeatErr) || !repeatErr.Exceeded || repeatErr.Attempts != 3 || ticks != 3 {
+		t.Fatalf("expected exceeded error after 3 ticks, actual %v after %d ticks", ticker.Err(), ticks)
+	}
+}
+
+func Test_Ticker_Context(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ticker := repeater.NewTicker(ctx, repeater.ConstantProgression(time.Hour), 5)
+	defer ticker.Stop()
+
+	<-ticker.C
+
+	cancel()
+
+	<-ticker.Done()
+
+	if _, ok := <-ticker.C; ok {
+		t.Fatal("channel must be closed")
+	}
+
+	err := ticker.Err()
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, actual %v", err)
+	}
+}
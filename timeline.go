@@ -0,0 +1,114 @@
+package repeater
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Timeline collects events of repeats run with a context returned by ContextWithTimeline,
+// useful for attaching to bug reports about slow calls
+type Timeline struct {
+	mu     sync.Mutex
+	events []AttemptEvent
+}
+
+// Events returns a copy of recorded events
+func (t *Timeline) Events() []AttemptEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	events := make([]AttemptEvent, len(t.events))
+	copy(events, t.events)
+
+	return events
+}
+
+// String formats events one per line with elapsed time since the repeat start
+func (t *Timeline) String() string {
+	var b strings.Builder
+
+	for _, event := range t.Events() {
+		fmt.Fprintf(&b, "+%s %s attempt=%d", event.Elapsed, event.Kind, event.Attempt)
+
+		switch event.Kind {
+		case AttemptFinished:
+			fmt.Fprintf(&b, " finished=%t", event.Finished)
+		case RetryScheduled:
+			fmt.Fprintf(&b, " delay=%s", event.Delay)
+		case RepeatFinished:
+			fmt.Fprintf(&b, " finished=%t exhausted=%t", event.Finished, event.Exhausted)
+		}
+
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+func (t *Timeline) add(event AttemptEvent) {
+	t.mu.Lock()
+	t.events = append(t.events, event)
+	t.mu.Unlock()
+}
+
+type timelineKey struct{}
+
+// ContextWithTimeline returns ctx carrying a new Timeline,
+// repeaters constructed with WithTimeline record events of RepeatContext calls into it
+func ContextWithTimeline(ctx context.Context) (context.Context, *Timeline) {
+	timeline := &Timeline{}
+
+	return context.WithValue(ctx, timelineKey{}, timeline), timeline
+}
+
+// TimelineFromContext returns Timeline stored by ContextWithTimeline or nil
+func TimelineFromContext(ctx context.Context) *Timeline {
+	timeline, _ := ctx.Value(timelineKey{}).(*Timeline)
+
+	return timeline
+}
+
+// WithTimeline enables recording into a Timeline found in the repeat context
+func WithTimeline() Option {
+	return WithRecorder(timelineRecorder{})
+}
+
+type timelineRecorder struct{}
+
+func (timelineRecorder) AttemptStarted(ctx context.Context, attempt uint64, elapsed time.Duration) {
+	record(ctx, AttemptEvent{Kind: AttemptStarted, Attempt: attempt, Elapsed: elapsed})
+}
+
+func (timelineRecorder) AttemptFinished(ctx context.Context, attempt uint64, elapsed time.Duration, finished bool) {
+	record(ctx, AttemptEvent{Kind: AttemptFinished, Attempt: attempt, Elapsed: elapsed, Finished: finished})
+
+	if finished {
+		record(ctx, AttemptEvent{Kind: RepeatFinished, Attempt: attempt, Elapsed: elapsed, Finished: true})
+	}
+}
+
+func (timelineRecorder) RetryScheduled(ctx context.Context, attempt uint64, elapsed, delay time.Duration) {
+	record(ctx, AttemptEvent{Kind: RetryScheduled, Attempt: attempt, Elapsed: elapsed, Delay: delay})
+}
+
+func (timelineRecorder) GaveUp(ctx context.Context, attempt uint64, elapsed time.Duration, exhausted bool) {
+	record(ctx, AttemptEvent{Kind: RepeatFinished, Attempt: attempt, Elapsed: elapsed, Exhausted: exhausted})
+}
+
+func (timelineRecorder) Stopped(ctx context.Context, attempt uint64, elapsed time.Duration) {
+	record(ctx, AttemptEvent{Kind: RepeatFinished, Attempt: attempt, Elapsed: elapsed, Stopped: true})
+}
+
+func record(ctx context.Context, event AttemptEvent) {
+	timeline := TimelineFromContext(ctx)
+	if timeline == nil {
+		return
+	}
+
+	event.Time = time.Now()
+
+	timeline.add(event)
+}
@@ -0,0 +1,77 @@
+package repeater_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_WithTimeline(t *testing.T) {
+	t.Parallel()
+
+	rp := repeater.New(
+		repeater.ConstantProgression(time.Millisecond),
+		repeater.WithTimeline(),
+	)
+
+	ctx, timeline := repeater.ContextWithTimeline(context.Background())
+
+	calls := 0
+
+	finished := rp.RepeatContext(ctx, func(context.Context) bool {
+		calls++
+
+		return calls == 2
+	}, 2)
+	if !finished {
+		t.Fatal("expected finished repeat")
+	}
+
+	expectedKinds := []repeater.AttemptEventKind{
+		repeater.AttemptStarted,
+		repeater.AttemptFinished,
+		repeater.RetryScheduled,
+		repeater.AttemptStarted,
+		repeater.AttemptFinished,
+		repeater.RepeatFinished,
+	}
+
+	if !slices.Equal(expectedKinds, eventKinds(timeline.Events())) {
+		t.Fatalf("wrong timeline event kinds, expected %v, actual %v", expectedKinds, eventKinds(timeline.Events()))
+	}
+
+	lines := strings.Split(strings.TrimSpace(timeline.String()), "\n")
+	if len(lines) != len(expectedKinds) {
+		t.Fatalf("wrong timeline lines count, expected %d, actual %d", len(expectedKinds), len(lines))
+	}
+
+	if !strings.HasPrefix(lines[0], "+") || !strings.HasSuffix(lines[0], " attempt started attempt=0") {
+		t.Fatalf("wrong first timeline line: %s", lines[0])
+	}
+
+	if !strings.HasSuffix(lines[2], "retry scheduled attempt=1 delay=1ms") {
+		t.Fatalf("wrong retry timeline line: %s", lines[2])
+	}
+}
+
+func Test_WithTimeline_NoTimelineInContext(t *testing.T) {
+	t.Parallel()
+
+	rp := repeater.New(
+		repeater.ConstantProgression(0),
+		repeater.WithTimeline(),
+	)
+
+	finished := rp.RepeatContext(context.Background(), func(context.Context) bool { return true }, 1)
+	if !finished {
+		t.Fatal("expected finished repeat")
+	}
+
+	if repeater.TimelineFromContext(context.Background()) != nil {
+		t.Fatal("expected nil timeline")
+	}
+}
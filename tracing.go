@@ -0,0 +1,92 @@
+package repeater
+
+import (
+	"context"
+	"time"
+)
+
+// Span is a minimal tracing span, adapt your tracer span
+// (e.g. go.opentelemetry.io/otel/trace.Span) to use it with WithSpan
+type Span interface {
+	AddEvent(name string, attributes map[string]any)
+	SetAttribute(key string, value any)
+}
+
+// SpanFromContext returns the active span or nil if ctx doesn't have one
+type SpanFromContext func(ctx context.Context) Span
+
+const (
+	RetrySpanEventName          = "retry"
+	AttemptFailedSpanEventName  = "attempt_failed"
+	RetriesExhaustedSpanAttrKey = "retries_exhausted"
+)
+
+// WithSpan records a span event for every scheduled retry with attempt number, elapsed time and delay,
+// an "attempt_failed" event with the "error" attribute for every RepeatErr attempt which returned an error,
+// and sets the "retries_exhausted" attribute once the repeat finished.
+func WithSpan(spanFromContext SpanFromContext) Option {
+	return WithRecorder(spanRecorder{spanFromContext: spanFromContext})
+}
+
+type spanRecorder struct {
+	spanFromContext SpanFromContext
+}
+
+func (spanRecorder) AttemptStarted(context.Context, uint64, time.Duration) {}
+
+func (s spanRecorder) AttemptFinished(ctx context.Context, _ uint64, _ time.Duration, finished bool) {
+	if !finished {
+		return
+	}
+
+	span := s.spanFromContext(ctx)
+	if span == nil {
+		return
+	}
+
+	span.SetAttribute(RetriesExhaustedSpanAttrKey, false)
+}
+
+func (s spanRecorder) AttemptFailed(ctx context.Context, attempt uint64, elapsed time.Duration, err error) {
+	span := s.spanFromContext(ctx)
+	if span == nil {
+		return
+	}
+
+	span.AddEvent(AttemptFailedSpanEventName, map[string]any{
+		"attempt": attempt,
+		"elapsed": elapsed.String(),
+		"error":   err.Error(),
+	})
+}
+
+func (s spanRecorder) RetryScheduled(ctx context.Context, attempt uint64, elapsed, delay time.Duration) {
+	span := s.spanFromContext(ctx)
+	if span == nil {
+		return
+	}
+
+	span.AddEvent(RetrySpanEventName, map[string]any{
+		"attempt": attempt,
+		"elapsed": elapsed.String(),
+		"delay":   delay.String(),
+	})
+}
+
+func (s spanRecorder) GaveUp(ctx context.Context, _ uint64, _ time.Duration, exhausted bool) {
+	span := s.spanFromContext(ctx)
+	if span == nil {
+		return
+	}
+
+	span.SetAttribute(RetriesExhaustedSpanAttrKey, exhausted)
+}
+
+func (s spanRecorder) Stopped(ctx context.Context, _ uint64, _ time.Duration) {
+	span := s.spanFromContext(ctx)
+	if span == nil {
+		return
+	}
+
+	span.SetAttribute(RetriesExhaustedSpanAttrKey, false)
+}
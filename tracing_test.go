@@ -0,0 +1,135 @@
+package repeater_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+)
+
+type spanEvent struct {
+	Name       string
+	Attributes map[string]any
+}
+
+type spanMock struct {
+	events     []spanEvent
+	attributes map[string]any
+}
+
+func (s *spanMock) AddEvent(name string, attributes map[string]any) {
+	s.events = append(s.events, spanEvent{Name: name, Attributes: attributes})
+}
+
+func (s *spanMock) SetAttribute(key string, value any) {
+	if s.attributes == nil {
+		s.attributes = make(map[string]any)
+	}
+
+	s.attributes[key] = value
+}
+
+type spanKey struct{}
+
+func spanFromContext(ctx context.Context) repeater.Span {
+	span, _ := ctx.Value(spanKey{}).(*spanMock)
+	if span == nil {
+		return nil
+	}
+
+	return span
+}
+
+func Test_WithSpan(t *testing.T) {
+	t.Parallel()
+
+	span := &spanMock{}
+	ctx := context.WithValue(context.Background(), spanKey{}, span)
+
+	rp := repeater.New(
+		repeater.NewArifmeticProgression(time.Millisecond, time.Millisecond),
+		repeater.WithSpan(spanFromContext),
+	)
+
+	finished := rp.RepeatContext(ctx, func(context.Context) bool { return false }, 2)
+	if finished {
+		t.Fatal("expected not finished repeat")
+	}
+
+	if len(span.events) != 2 {
+		t.Fatalf("wrong span events count, expected 2, actual %d", len(span.events))
+	}
+
+	for i, event := range span.events {
+		expectedAttempt := uint64(i + 1)
+		expectedDelay := (time.Millisecond * time.Duration(i+1)).String()
+
+		if event.Name != repeater.RetrySpanEventName {
+			t.Fatalf("wrong span event name, expected %s, actual %s", repeater.RetrySpanEventName, event.Name)
+		}
+
+		if event.Attributes["attempt"] != expectedAttempt {
+			t.Fatalf("wrong attempt attribute, expected %d, actual %v", expectedAttempt, event.Attributes["attempt"])
+		}
+
+		if event.Attributes["delay"] != expectedDelay {
+			t.Fatalf("wrong delay attribute, expected %s, actual %v", expectedDelay, event.Attributes["delay"])
+		}
+	}
+
+	if span.attributes[repeater.RetriesExhaustedSpanAttrKey] != true {
+		t.Fatalf("wrong %s attribute, expected true, actual %v", repeater.RetriesExhaustedSpanAttrKey, span.attributes[repeater.RetriesExhaustedSpanAttrKey])
+	}
+}
+
+func Test_WithSpan_NoSpanInContext(t *testing.T) {
+	t.Parallel()
+
+	rp := repeater.New(
+		repeater.ConstantProgression(0),
+		repeater.WithSpan(spanFromContext),
+	)
+
+	finished := rp.Repeat(func() bool { return true }, 2)
+	if !finished {
+		t.Fatal("expected finished repeat")
+	}
+}
+
+func Test_WithSpan_AttemptError(t *testing.T) {
+	t.Parallel()
+
+	span := &spanMock{}
+	ctx := context.WithValue(context.Background(), spanKey{}, span)
+
+	rp := repeater.New(
+		repeater.ConstantProgression(0),
+		repeater.WithSpan(spanFromContext),
+	)
+
+	_ = rp.RepeatErr(ctx, func(context.Context) error { return io.ErrUnexpectedEOF }, 1)
+
+	expected := []spanEvent{
+		{Name: repeater.AttemptFailedSpanEventName, Attributes: map[string]any{"attempt": uint64(0), "error": "unexpected EOF"}},
+		{Name: repeater.RetrySpanEventName, Attributes: map[string]any{"attempt": uint64(1), "delay": "0s"}},
+		{Name: repeater.AttemptFailedSpanEventName, Attributes: map[string]any{"attempt": uint64(1), "error": "unexpected EOF"}},
+	}
+
+	if len(span.events) != len(expected) {
+		t.Fatalf("wrong span events count, expected %d, actual %d", len(expected), len(span.events))
+	}
+
+	for i, event := range span.events {
+		if event.Name != expected[i].Name {
+			t.Fatalf("wrong span event name, expected %s, actual %s", expected[i].Name, event.Name)
+		}
+
+		for key, value := range expected[i].Attributes {
+			if event.Attributes[key] != value {
+				t.Fatalf("wrong %s attribute of %s event, expected %v, actual %v", key, event.Name, value, event.Attributes[key])
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package repeater
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+// defaultWaitProgression starts with 50ms and doubles up to 1s
+var defaultWaitProgression = NewCappedProgression(NewExponentialProgression(time.Millisecond*50, 2), time.Second)
+
+type waitOptions struct {
+	progression DurationProgression
+}
+
+type WaitOption func(o *waitOptions)
+
+// WithWaitProgression replaces the progression of wait helpers,
+// by default it starts with 50ms and doubles up to 1s
+func WithWaitProgression(progression DurationProgression) WaitOption {
+	return func(o *waitOptions) {
+		o.progression = progression
+	}
+}
+
+// WaitForFunc polls f until it returns nil, errors of f mean "not ready yet",
+// so waiting is limited only by ctx, e.g. a startup or a test timeout.
+// Returns *Error wrapping the last f error and ctx.Err() when ctx is done first
+func WaitForFunc(ctx context.Context, f func(ctx context.Context) error, opts ...WaitOption) error {
+	o := waitOptions{
+		progression: defaultWaitProgression,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var lastErr error
+
+	err := Poll(ctx, o.progression, func(ctx context.Context) (bool, error) {
+		lastErr = f(ctx)
+
+		return lastErr == nil, nil
+	})
+	if err == nil {
+		return nil
+	}
+
+	repeatErr, ok := err.(*Error)
+	if !ok || lastErr == nil {
+		return err
+	}
+
+	repeatErr.Last = errors.Join(lastErr, repeatErr.Last)
+
+	return repeatErr
+}
+
+// WaitForTCP waits until a TCP connection to addr is accepted, see WaitForFunc
+func WaitForTCP(ctx context.Context, addr string, opts ...WaitOption) error {
+	var dialer net.Dialer
+
+	return WaitForFunc(ctx, func(ctx context.Context) error {
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+
+		return conn.Close()
+	}, opts...)
+}
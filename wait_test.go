@@ -0,0 +1,98 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amidgo/repeater"
+	"github.com/amidgo/repeater/repeatertest"
+)
+
+func Test_WaitForFunc(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+
+	err := repeater.WaitForFunc(context.Background(), func(context.Context) error {
+		calls++
+
+		if calls < 3 {
+			return io.ErrUnexpectedEOF
+		}
+
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("unexpected result, error %v after %d calls", err, calls)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	err = repeater.WaitForFunc(ctx, func(context.Context) error {
+		return io.ErrUnexpectedEOF
+	})
+
+	if !errors.Is(err, io.ErrUnexpectedEOF) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected error wraps last func error and ctx error, actual %v", err)
+	}
+}
+
+func Test_WaitForTCP(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err = repeater.WaitForTCP(ctx, ln.Addr().String())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	addr := ln.Addr().String()
+	ln.Close()
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	err = repeater.WaitForTCP(ctx, addr)
+	if err == nil {
+		t.Fatal("expected error for closed listener")
+	}
+}
+
+func Test_WaitForFunc_WithWaitProgression(t *testing.T) {
+	t.Parallel()
+
+	spy := repeatertest.NewBackoffSpy(t, time.Millisecond)
+
+	calls := 0
+
+	err := repeater.WaitForFunc(context.Background(), func(context.Context) error {
+		calls++
+
+		if calls < 3 {
+			return io.ErrUnexpectedEOF
+		}
+
+		return nil
+	}, repeater.WithWaitProgression(spy))
+	if err != nil || calls != 3 {
+		t.Fatalf("unexpected result, error %v after %d calls", err, calls)
+	}
+
+	if !slices.Equal([]uint64{0, 1}, spy.Attempts()) {
+		t.Fatalf("wrong progression attempts, expected [0 1], actual %v", spy.Attempts())
+	}
+}
@@ -0,0 +1,61 @@
+package repeater
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Warnings collects non-fatal errors of repeat funcs, see ContextWithWarnings
+type Warnings struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+type warningsKey struct{}
+
+// ContextWithWarnings returns ctx collecting warnings of repeat funcs called with it,
+// warnings are kept whether the repeat succeeded or not, so intermittent degradations,
+// e.g. a fallback replica was used, are visible without failing the call
+func ContextWithWarnings(ctx context.Context) (context.Context, *Warnings) {
+	w := &Warnings{}
+
+	return context.WithValue(ctx, warningsKey{}, w), w
+}
+
+// Warn adds a non-fatal err to the warnings of ctx, reports false if ctx doesn't collect warnings
+func Warn(ctx context.Context, err error) bool {
+	w, ok := ctx.Value(warningsKey{}).(*Warnings)
+	if !ok || err == nil {
+		return false
+	}
+
+	w.mu.Lock()
+	w.errs = append(w.errs, err)
+	w.mu.Unlock()
+
+	return true
+}
+
+// Errors returns the collected warnings in order
+func (w *Warnings) Errors() []error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]error(nil), w.errs...)
+}
+
+// Err returns the joined warnings, nil if there are none
+func (w *Warnings) Err() error {
+	return errors.Join(w.Errors()...)
+}
+
+// warningsFromContext returns the warnings collected in ctx, nil if ctx doesn't collect them
+func warningsFromContext(ctx context.Context) []error {
+	w, ok := ctx.Value(warningsKey{}).(*Warnings)
+	if !ok {
+		return nil
+	}
+
+	return w.Errors()
+}
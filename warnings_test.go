@@ -0,0 +1,56 @@
+package repeater_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/amidgo/repeater"
+)
+
+func Test_Warnings(t *testing.T) {
+	t.Parallel()
+
+	errStaleReplica := errors.New("stale replica")
+	errTemporary := errors.New("temporary")
+
+	ctx, warnings := repeater.ContextWithWarnings(context.Background())
+
+	calls := 0
+
+	err := repeater.RepeatErr(ctx, repeater.ConstantProgression(0), func(ctx context.Context) error {
+		calls++
+
+		if calls == 1 {
+			return errTemporary
+		}
+
+		if !repeater.Warn(ctx, errStaleReplica) {
+			t.Error("warning is not collected")
+		}
+
+		return nil
+	}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if errs := warnings.Errors(); len(errs) != 1 || !errors.Is(warnings.Err(), errStaleReplica) {
+		t.Fatalf("unexpected warnings %v", errs)
+	}
+
+	err = repeater.RepeatErr(ctx, repeater.ConstantProgression(0), func(ctx context.Context) error {
+		repeater.Warn(ctx, errStaleReplica)
+
+		return errTemporary
+	}, 0)
+
+	repeatErr := &repeater.Error{}
+	if !errors.As(err, &repeatErr) || len(repeatErr.Warnings) != 2 {
+		t.Fatalf("expected *repeater.Error with 2 warnings, actual %v", err)
+	}
+
+	if repeater.Warn(context.Background(), errStaleReplica) {
+		t.Fatal("warning without collector must not be collected")
+	}
+}